@@ -0,0 +1,70 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+)
+
+// apiVersionRegexp matches an API version path segment such as "v1"
+var apiVersionRegexp = regexp.MustCompile(`^v\d+$`)
+
+// APIInfo describes the releases API a client talks to
+type APIInfo struct {
+	// The base URL requests are sent to
+	BaseURL string
+	// The API version selected by the base URL, e.g. "v1", or empty if
+	// its last path segment does not name one
+	Version string
+	// The number of products listed by the probe request
+	Products int
+	// The request ID of the probe request, if the API reported one
+	RequestID string
+}
+
+// APIInfo probes the releases API and describes it. The API exposes no
+// version or metadata endpoint, so the version is taken from the base
+// URL and confirmed by requesting the product list under it, which
+// fails if the deployment does not serve that version or responds with
+// a different shape. If WithRequireAPIVersion was used, the version is
+// also checked against the requirement.
+func (c *Client) APIInfo(ctx context.Context) (APIInfo, error) {
+	info := APIInfo{BaseURL: c.URL, Version: apiVersion(c.URL)}
+	if err := c.checkAPIVersion(); err != nil {
+		return info, err
+	}
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return info, fmt.Errorf("can't confirm releases API version %q at %s: %w", info.Version, c.URL, err)
+	}
+	info.Products = len(products)
+	info.RequestID = c.LastRequestID()
+	return info, nil
+}
+
+// checkAPIVersion checks the version selected by the base URL against
+// the one required with WithRequireAPIVersion, if any
+func (c *Client) checkAPIVersion() error {
+	if c.requireAPIVersion == "" {
+		return nil
+	}
+	if v := apiVersion(c.URL); v != c.requireAPIVersion {
+		return fmt.Errorf("releases API at %s is version %q, but %q is required", c.URL, v, c.requireAPIVersion)
+	}
+	return nil
+}
+
+// apiVersion returns the API version named by the last path segment of
+// a base URL, if any
+func apiVersion(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if v := path.Base(u.Path); apiVersionRegexp.MatchString(v) {
+		return v
+	}
+	return ""
+}
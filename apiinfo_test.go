@@ -0,0 +1,37 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestNewVerifiedClient(t *testing.T) {
+	products := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/products" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`["consul","vault"]`))
+	})
+	_, srv := newTestClient(t, products)
+	ctx := context.Background()
+
+	c, err := NewVerifiedClient(ctx, WithBaseURL(srv.URL+"/v1"), WithRequireAPIVersion("v1"))
+	if err != nil {
+		t.Fatalf("NewVerifiedClient: %v", err)
+	}
+	if info, err := c.APIInfo(ctx); err != nil || info.Version != "v1" || info.Products != 2 {
+		t.Errorf("APIInfo() = %+v, %v, want version v1 with 2 products", info, err)
+	}
+
+	// The live API does not serve v2, although the base URL selects it
+	if _, err := NewVerifiedClient(ctx, WithBaseURL(srv.URL+"/v2"), WithRequireAPIVersion("v2")); err == nil {
+		t.Error("NewVerifiedClient: got no error for a version the API does not serve")
+	}
+
+	// The base URL selects a different version than required
+	if _, err := NewClient(WithBaseURL(srv.URL+"/v1"), WithRequireAPIVersion("v2")); err == nil {
+		t.Error("NewClient: got no error for a base URL selecting another version")
+	}
+}
@@ -0,0 +1,297 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency bounds the number of concurrent requests issued
+// by helpers that fan out across several products
+const defaultConcurrency = 4
+
+// ProductErrors maps product names to the error encountered while
+// fetching data for that product
+type ProductErrors map[string]error
+
+func (e ProductErrors) Error() string {
+	products := make([]string, 0, len(e))
+	for p := range e {
+		products = append(products, p)
+	}
+	sort.Strings(products)
+	msgs := make([]string, 0, len(products))
+	for _, p := range products {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", p, e[p]))
+	}
+	return fmt.Sprintf("error fetching %d product(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// GetLatestReleases fetches the latest release of each of the provided
+// products concurrently, returning a map keyed by product. Failures do
+// not abort the other products; they are collected and returned as a
+// ProductErrors alongside the releases that were fetched successfully.
+// Products not yet dispatched when ctx is cancelled report ctx.Err().
+func (c *Client) GetLatestReleases(ctx context.Context, products []string) (map[string]*Release, error) {
+	var mu sync.Mutex
+	res := map[string]*Release{}
+	errs := ProductErrors{}
+
+	c.forEachProduct(ctx, products, func(product string) {
+		r, err := c.latestRelease(ctx, product)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[product] = err
+			return
+		}
+		res[product] = r
+	}, func(product string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[product] = err
+	})
+
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// Catalog fetches the product list and resolves the latest stable
+// release of each product concurrently with GetLatestStableRelease,
+// returning a map keyed by product. Failures, including products without
+// any stable release, do not abort the other products; they are
+// collected and returned as a ProductErrors alongside the releases that
+// were resolved. Products not yet dispatched when ctx is cancelled
+// report ctx.Err().
+func (c *Client) Catalog(ctx context.Context) (map[string]*Release, error) {
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	res := map[string]*Release{}
+	errs := ProductErrors{}
+
+	c.forEachProduct(ctx, products, func(product string) {
+		r, err := c.GetLatestStableRelease(ctx, product)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[product] = err
+			return
+		}
+		res[product] = r
+	}, func(product string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[product] = err
+	})
+
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// GetAllProductReleasesSince fetches the product list and, for each
+// product concurrently, pages back through its releases to those created
+// at or after since, returning them keyed by product. This issues at
+// least one request per product. Failures do not abort the sweep; they
+// are collected and returned as a ProductErrors, and the releases read
+// for a product before its failure are still included.
+func (c *Client) GetAllProductReleasesSince(ctx context.Context, since time.Time) (map[string]ReleasesResponse, error) {
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	res := map[string]ReleasesResponse{}
+	errs := ProductErrors{}
+	to := c.now()
+
+	c.forEachProduct(ctx, products, func(product string) {
+		releases, err := c.GetReleasesBetween(ctx, product, since, to)
+		mu.Lock()
+		defer mu.Unlock()
+		if len(releases) > 0 || err == nil {
+			res[product] = releases
+		}
+		if err != nil {
+			errs[product] = err
+		}
+	}, func(product string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[product] = err
+	})
+
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// GetReleasesAllLicenseClasses requests a page of both the enterprise
+// and the open source releases of a product concurrently, each using
+// opts (whose LicenseClass is ignored), and merges them in the order of
+// StableSort, so up to twice opts.Limit releases are returned. A
+// release returned by both requests is included once. If one request
+// fails, the releases of the other are returned along with its error.
+func (c *Client) GetReleasesAllLicenseClasses(ctx context.Context, product string, opts *ReleaseOptions) (ReleasesResponse, error) {
+	pages := make([]ReleasesResponse, len(licenseClasses))
+	errs := make([]error, len(licenseClasses))
+	var wg sync.WaitGroup
+	for i, lc := range licenseClasses {
+		options := ReleaseOptions{}
+		if opts != nil {
+			options = *opts
+		}
+		options.LicenseClass = lc
+		wg.Add(1)
+		go func(i int, options ReleaseOptions) {
+			defer wg.Done()
+			pages[i], errs[i] = c.GetReleases(ctx, product, &options)
+		}(i, options)
+	}
+	wg.Wait()
+
+	// Merge the pages, dropping duplicates
+	res := ReleasesResponse{}
+	seen := map[string]bool{}
+	for _, page := range pages {
+		for _, r := range page {
+			key := r.Name + "@" + r.Version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			res = append(res, r)
+		}
+	}
+	res.StableSort()
+	for _, err := range errs {
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// forEachProduct calls fn for each product using a bounded pool of
+// goroutines. Once ctx is cancelled no further products are dispatched
+// and skipped is called for each remaining product instead.
+func (c *Client) forEachProduct(ctx context.Context, products []string, fn func(product string), skipped func(product string, err error)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, defaultConcurrency)
+	for _, product := range products {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			skipped(product, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(product string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(product)
+		}(product)
+	}
+	wg.Wait()
+}
+
+// latestRelease returns the most recently created release of a product,
+// or ErrNoReleases if it has none
+func (c *Client) latestRelease(ctx context.Context, product string, opts ...CallOption) (*Release, error) {
+	releases, err := c.GetReleases(ctx, product, &ReleaseOptions{Limit: 1}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, ErrNoReleases
+	}
+	return &releases[0], nil
+}
+
+// GetLatestStableRelease returns the most recently created release of a
+// product that is not a prerelease, paging past newer prereleases as
+// needed, or ErrNoReleases if it has none
+func (c *Client) GetLatestStableRelease(ctx context.Context, product string) (*Release, error) {
+	releases, err := c.GetReleasesFiltered(ctx, product, nil, 1, 0, func(r Release) bool { return !isPrerelease(r) })
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, ErrNoReleases
+	}
+	return &releases[0], nil
+}
+
+// maxLatestWithMetadata bounds the number of releases fetched by
+// GetLatestReleasesWithMetadata
+const maxLatestWithMetadata = 100
+
+// GetLatestReleasesWithMetadata returns the n most recently created
+// releases of a product with their full metadata, newest first. The
+// releases endpoint already returns builds, so the metadata endpoint is
+// only queried, concurrently, for releases listed without any builds.
+// n must be between 1 and 100.
+func (c *Client) GetLatestReleasesWithMetadata(ctx context.Context, product string, n int) (ReleasesResponse, error) {
+	if n < 1 || n > maxLatestWithMetadata {
+		return nil, fmt.Errorf("n must be between 1 and %d, got %d", maxLatestWithMetadata, n)
+	}
+
+	// Fetch the latest n release summaries
+	limit := n
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	res, err := c.GetReleasesFiltered(ctx, product, &ReleaseOptions{Limit: limit}, n, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enrich the releases lacking builds using a bounded pool
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, defaultConcurrency)
+	for i := range res {
+		if len(res[i].Builds) > 0 {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			md, err := c.GetReleaseMetadata(ctx, product, res[i].Version)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			res[i] = Release(*md)
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return res, nil
+}
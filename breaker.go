@@ -0,0 +1,91 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without issuing a request while the circuit
+// breaker configured with WithCircuitBreaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker opens after a number of consecutive failures and
+// short-circuits requests until its cooldown has elapsed, after which a
+// single trial request is let through to test recovery
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+}
+
+// allow returns ErrCircuitOpen if a request must not be issued
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// A trial request is already in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a request
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A cancelled request says nothing about the upstream's health
+	if errors.Is(err, context.Canceled) {
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+		}
+		return
+	}
+	if !isBreakerFailure(err) {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isBreakerFailure reports whether an error indicates the upstream is
+// unhealthy. Transport errors and 5xx responses count; client errors
+// such as a 404 for an unknown product do not.
+func isBreakerFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrNotModified) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var decodeErr *DecodeError
+	return !errors.As(err, &decodeErr) && !errors.Is(err, ErrEmptyResponse)
+}
@@ -0,0 +1,45 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerCooldownIgnoresClock(t *testing.T) {
+	var fail int32 = 1
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`["vault"]`))
+	}),
+		WithClock(func() time.Time { return time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC) }),
+		WithCircuitBreaker(1, time.Minute),
+	)
+	wall := time.Now()
+	c.breaker.now = func() time.Time { return wall }
+	ctx := context.Background()
+
+	// Open the breaker
+	if _, err := c.GetProducts(ctx); err == nil {
+		t.Fatal("expected the first request to fail")
+	}
+	if _, err := c.GetProducts(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v during the cooldown, want ErrCircuitOpen", err)
+	}
+
+	// The fixed as-of clock must not keep the breaker open
+	atomic.StoreInt32(&fail, 0)
+	wall = wall.Add(time.Minute)
+	if _, err := c.GetProducts(ctx); err != nil {
+		t.Fatalf("got %v after the cooldown, want the trial request to succeed", err)
+	}
+	if _, err := c.GetProducts(ctx); err != nil {
+		t.Fatalf("got %v after the trial request, want the breaker closed", err)
+	}
+}
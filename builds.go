@@ -0,0 +1,147 @@
+package hashicorpreleases
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrBuildNotFound is returned by BuildStrict when a release has no
+	// build for the requested platform
+	ErrBuildNotFound = errors.New("no build found")
+	// ErrUnsupportedBuild is returned by BuildStrict when the only build
+	// for the requested platform is not supported by HashiCorp
+	ErrUnsupportedBuild = errors.New("not supported by HashiCorp")
+	// ErrNoBuilds is returned by BuildStrict when a release has no builds
+	// at all, e.g. because they have not been published yet. It wraps
+	// ErrBuildNotFound.
+	ErrNoBuilds = fmt.Errorf("%w: release has no builds", ErrBuildNotFound)
+)
+
+// archAliases maps alternative architecture names to the names
+// HashiCorp uses for its builds
+var archAliases = map[Arch]Arch{
+	"x86_64":  ArchAMD64,
+	"aarch64": ArchARM64,
+	"i386":    Arch386,
+	"i686":    Arch386,
+}
+
+// normalizeArch returns the HashiCorp build name of an architecture
+func normalizeArch(arch Arch) Arch {
+	if a, ok := archAliases[arch]; ok {
+		return a
+	}
+	return arch
+}
+
+// Build returns the build of the release for the given operating system
+// and architecture, e.g. r.Build(OSLinux, ArchAMD64). If both a supported
+// and an unsupported build match, the supported one is returned. The
+// second return value reports whether a matching build was found.
+// It is false, without an error, both when the platform is not built and
+// when the release has no builds at all; use HasBuilds to tell these
+// apart.
+func (r Release) Build(os OS, arch Arch) (*Build, bool) {
+	arch = normalizeArch(arch)
+	var match *Build
+	for i := range r.Builds {
+		b := &r.Builds[i]
+		if OS(b.OperatingSystem) != os || Arch(b.Architecture) != arch {
+			continue
+		}
+		if !b.Unsupported {
+			return b, true
+		}
+		if match == nil {
+			match = b
+		}
+	}
+	return match, match != nil
+}
+
+// BuildStrict is like Build but returns an error instead of an
+// unsupported build: ErrUnsupportedBuild if the platform is built but
+// not supported by HashiCorp, or ErrBuildNotFound if it is not built.
+// If the release has no builds at all, the error is ErrNoBuilds, which
+// also matches ErrBuildNotFound.
+func (r Release) BuildStrict(os OS, arch Arch) (*Build, error) {
+	if !r.HasBuilds() {
+		return nil, fmt.Errorf("%w in %s %s", ErrNoBuilds, r.Name, r.Version)
+	}
+	b, ok := r.Build(os, arch)
+	if !ok {
+		return nil, fmt.Errorf("%w for %s/%s in %s %s", ErrBuildNotFound, os, arch, r.Name, r.Version)
+	}
+	if b.Unsupported {
+		return nil, fmt.Errorf("%s/%s in %s %s is built but %w", os, arch, r.Name, r.Version, ErrUnsupportedBuild)
+	}
+	return b, nil
+}
+
+// HasBuilds reports whether any builds of the release have been
+// published. Release metadata can legitimately list no builds, e.g.
+// shortly after a release is created.
+func (r Release) HasBuilds() bool {
+	return len(r.Builds) > 0
+}
+
+// CurrentPlatformBuild returns the build of the release matching the
+// operating system and architecture of the running program
+func (r Release) CurrentPlatformBuild() (*Build, bool) {
+	return r.Build(OS(runtime.GOOS), Arch(runtime.GOARCH))
+}
+
+// Filename returns the file name of the build's artifact, which is the
+// last segment of its URL path, e.g. "vault_1.15.0_linux_amd64.zip".
+// Query strings and trailing slashes are ignored. An empty string is
+// returned if the URL cannot be parsed or has no path.
+func (b Build) Filename() string {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return ""
+	}
+	p := strings.TrimRight(u.Path, "/")
+	if p == "" {
+		return ""
+	}
+	return path.Base(p)
+}
+
+// DiffBuilds compares the builds of two releases by operating system and
+// architecture, returning the builds of b for platforms a lacks (added)
+// and the builds of a for platforms b lacks (removed), sorted by os then
+// arch. A platform built by both is in neither list, even if its support
+// status changed; compare the Unsupported flags of a.Build(os, arch) and
+// b.Build(os, arch) to detect that.
+func DiffBuilds(a, b Release) (added, removed []Build) {
+	return missingBuilds(b, a), missingBuilds(a, b)
+}
+
+// missingBuilds returns the builds of r for platforms other lacks
+func missingBuilds(r, other Release) []Build {
+	res := []Build{}
+	seen := map[string]bool{}
+	for _, b := range r.Builds {
+		key := b.OperatingSystem + "/" + b.Architecture
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := other.Build(OS(b.OperatingSystem), Arch(b.Architecture)); !ok {
+			res = append(res, b)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].OperatingSystem != res[j].OperatingSystem {
+			return res[i].OperatingSystem < res[j].OperatingSystem
+		}
+		return res[i].Architecture < res[j].Architecture
+	})
+	return res
+}
@@ -0,0 +1,40 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReleaseWithoutBuilds(t *testing.T) {
+	payloads := map[string]string{
+		"empty":   `"builds":[],`,
+		"null":    `"builds":null,`,
+		"missing": ``,
+	}
+	for name, builds := range payloads {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{%s"name":"vault","version":"1.16.0","status":{"state":"supported"}}`, builds)
+			}))
+			m, err := c.GetReleaseMetadata(context.Background(), "vault", "1.16.0")
+			if err != nil {
+				t.Fatalf("GetReleaseMetadata: %v", err)
+			}
+			r := Release(*m)
+
+			if r.HasBuilds() {
+				t.Error("HasBuilds() = true, want false")
+			}
+			if b, ok := r.Build(OSLinux, ArchAMD64); b != nil || ok {
+				t.Errorf("Build() = (%v, %t), want (nil, false)", b, ok)
+			}
+			_, err = r.BuildStrict(OSLinux, ArchAMD64)
+			if !errors.Is(err, ErrNoBuilds) || !errors.Is(err, ErrBuildNotFound) {
+				t.Errorf("BuildStrict() error = %v, want ErrNoBuilds matching ErrBuildNotFound", err)
+			}
+		})
+	}
+}
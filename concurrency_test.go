@@ -0,0 +1,56 @@
+package hashicorpreleases_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	hashicorpreleases "github.com/rizkybiz/hashicorpreleases-go"
+)
+
+// TestClientConcurrentUse hammers a shared client from many goroutines
+// and is meant to be run with the race detector, go test -race
+func TestClientConcurrentUse(t *testing.T) {
+	srv := fixtureServer(t, nil)
+	c, err := hashicorpreleases.NewClient(
+		hashicorpreleases.WithBaseURL(srv.URL+"/v1"),
+		hashicorpreleases.WithProductsRefreshInterval(time.Millisecond),
+		hashicorpreleases.WithCircuitBreaker(1000, time.Second),
+		hashicorpreleases.WithMaxConcurrency(4),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 32*5)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Products(ctx); err != nil {
+				errs <- err
+			}
+			if _, err := c.ProductExists(ctx, "vault"); err != nil {
+				errs <- err
+			}
+			if err := c.RefreshProducts(ctx); err != nil {
+				errs <- err
+			}
+			if _, err := c.GetReleases(ctx, "vault", nil); err != nil {
+				errs <- err
+			}
+			if _, err := c.GetReleaseMetadata(ctx, "vault", "1.15.0"); err != nil {
+				errs <- err
+			}
+			c.LastRequestID()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,93 @@
+package hashicorpreleases
+
+import (
+	"fmt"
+	"strings"
+)
+
+// constraintOps are the supported constraint operators, longest first so
+// that ">=" is not mistaken for ">"
+var constraintOps = []string{">=", "<=", "!=", "~>", ">", "<", "="}
+
+// constraint is a single version constraint such as ">= 1.14"
+type constraint struct {
+	op      string
+	version semver
+	// The number of numeric segments given, which sets the upper bound
+	// of the "~>" operator
+	segments int
+}
+
+// constraints is a set of version constraints that must all hold
+type constraints []constraint
+
+// parseConstraints parses comma-separated version constraints in the
+// syntax used across HashiCorp tools, e.g. ">= 1.14, < 1.16" or
+// "~> 1.15.2". A bare version means "=".
+func parseConstraints(s string) (constraints, error) {
+	res := constraints{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+		op := "="
+		for _, o := range constraintOps {
+			if strings.HasPrefix(term, o) {
+				op = o
+				term = strings.TrimSpace(term[len(o):])
+				break
+			}
+		}
+		v, err := parseVersion(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		numeric := strings.TrimPrefix(term, "v")
+		if i := strings.IndexAny(numeric, "-+"); i >= 0 {
+			numeric = numeric[:i]
+		}
+		res = append(res, constraint{op: op, version: v, segments: strings.Count(numeric, ".") + 1})
+	}
+	return res, nil
+}
+
+// check reports whether v satisfies all of the constraints
+func (cs constraints) check(v semver) bool {
+	for _, c := range cs {
+		if !c.check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// check reports whether v satisfies the constraint
+func (c constraint) check(v semver) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+
+	// "~>" allows the rightmost given segment to increase, e.g. "~> 1.15"
+	// means ">= 1.15, < 2.0" and "~> 1.15.2" means ">= 1.15.2, < 1.16.0"
+	if cmp < 0 {
+		return false
+	}
+	upper := semver{major: c.version.major + 1}
+	if c.segments == 3 {
+		upper = semver{major: c.version.major, minor: c.version.minor + 1}
+	}
+	return semver{major: v.major, minor: v.minor, patch: v.patch}.compare(upper) < 0
+}
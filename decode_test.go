@@ -0,0 +1,94 @@
+package hashicorpreleases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// roundTripFunc is an http.RoundTripper implemented by a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// staticClient returns a client whose requests are all answered with a
+// 200 response carrying body, without touching the network
+func staticClient(tb testing.TB, body []byte, opts ...ClientOption) *Client {
+	tb.Helper()
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	opts = append([]ClientOption{
+		WithBaseURL("https://api.releases.hashicorp.com/v1"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+	}, opts...)
+	c, err := NewClient(opts...)
+	if err != nil {
+		tb.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// fullReleasesPage returns a representative page of maxLimit releases,
+// built from the recorded metadata of Vault 1.15.0
+func fullReleasesPage(tb testing.TB) []byte {
+	tb.Helper()
+	data, err := os.ReadFile("releasestest/testdata/v1/releases/vault/1.15.0.json")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var release map[string]interface{}
+	if err := json.Unmarshal(data, &release); err != nil {
+		tb.Fatal(err)
+	}
+	page := make([]map[string]interface{}, maxLimit)
+	for i := range page {
+		page[i] = release
+	}
+	body, err := json.Marshal(page)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return body
+}
+
+func BenchmarkDecodeReleases(b *testing.B) {
+	c := staticClient(b, fullReleasesPage(b))
+	ctx := context.Background()
+	options := &ReleaseOptions{Limit: maxLimit}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetReleases(ctx, "vault", options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeReleaseMetadata(b *testing.B) {
+	data, err := os.ReadFile("releasestest/testdata/v1/releases/vault/1.15.0.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := staticClient(b, data)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetReleaseMetadata(ctx, "vault", "1.15.0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
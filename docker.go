@@ -0,0 +1,50 @@
+package hashicorpreleases
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// RegistryDockerHub selects the DockerHub image of a release
+	RegistryDockerHub = "dockerhub"
+	// RegistryECR selects the Amazon ECR-Public image of a release
+	RegistryECR = "ecr"
+)
+
+// DockerImage returns a fully-qualified, pullable image reference for the
+// release on the requested registry (RegistryDockerHub or RegistryECR),
+// e.g. "docker.io/hashicorp/vault:1.15.0". The repository is taken from
+// the registry URL of the release and the tag from its DockerNameTag.
+func (r Release) DockerImage(registry string) (string, error) {
+
+	// Determine the pull host and repository path for the registry
+	var host, registryURL, prefix string
+	switch registry {
+	case RegistryDockerHub:
+		host, registryURL, prefix = "docker.io", r.DockerhubURL, "/r/"
+	case RegistryECR:
+		host, registryURL, prefix = "public.ecr.aws", r.AmazonECRURL, "/"
+	default:
+		return "", fmt.Errorf("unknown registry %q, must be %q or %q", registry, RegistryDockerHub, RegistryECR)
+	}
+	if registryURL == "" {
+		return "", fmt.Errorf("release %s has no %s image", r.Version, registry)
+	}
+	u, err := url.Parse(registryURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s URL %q: %w", registry, registryURL, err)
+	}
+	repo := strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+	if repo == "" {
+		return "", fmt.Errorf("no repository in %s URL %q", registry, registryURL)
+	}
+
+	// Take the tag from the name:tag reference
+	i := strings.LastIndex(r.DockerNameTag, ":")
+	if i < 0 || i == len(r.DockerNameTag)-1 {
+		return "", fmt.Errorf("release %s has no docker tag", r.Version)
+	}
+	return fmt.Sprintf("%s/%s:%s", host, repo, r.DockerNameTag[i+1:]), nil
+}
@@ -0,0 +1,308 @@
+package hashicorpreleases
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrIncompleteDownload is returned when a download ends before the
+// number of bytes advertised by its Content-Length header was received
+var ErrIncompleteDownload = errors.New("incomplete download")
+
+// ErrStalledDownload is returned when no bytes of a download were
+// received for the duration set with WithDownloadStallTimeout
+var ErrStalledDownload = errors.New("download stalled")
+
+// DownloadBuild downloads the artifact of a build and writes it to w,
+// returning the number of bytes written. Downloads are not subject to
+// the client's API timeout; they are bounded by ctx and, if set, the
+// timeout configured with WithDownloadTimeout.
+func (c *Client) DownloadBuild(ctx context.Context, b Build, w io.Writer) (int64, error) {
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, "GET", b.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.setHeaders(req)
+	if b.Unsupported {
+		c.warnf("downloading %s, which is not supported by HashiCorp", b.Filename())
+	}
+
+	// Issue the request and stream the body into w
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	n, err := c.download(req, w)
+	c.observeDownload(n, err)
+	return n, err
+}
+
+// BuildSize returns the size in bytes of the artifact of a build, as
+// reported by the Content-Length of a HEAD request, without downloading
+// it. The request is subject to the client's headers, concurrency limit
+// and retry policy. -1 is returned with an error if the server rejects
+// HEAD requests or does not report a length.
+func (c *Client) BuildSize(ctx context.Context, b Build) (int64, error) {
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, "HEAD", b.URL, nil)
+	if err != nil {
+		return -1, err
+	}
+	c.setHeaders(req)
+
+	// Issue the request
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return -1, err
+	}
+	defer release()
+	res, err := c.do(c.HTTPClient, req, c.retryPolicy)
+	if err != nil {
+		c.observeRequest(0, 0, err)
+		return -1, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		err := &APIError{StatusCode: res.StatusCode, URL: b.URL, RequestID: responseRequestID(res)}
+		c.observeRequest(res.StatusCode, 0, err)
+		return -1, err
+	}
+	c.observeRequest(res.StatusCode, 0, nil)
+	if res.ContentLength < 0 {
+		return -1, fmt.Errorf("no Content-Length reported for %s", b.URL)
+	}
+	return res.ContentLength, nil
+}
+
+// DownloadBuildToDir downloads the artifact of b, a build of r, into dir,
+// creating directories as needed, and returns the path of the file
+// written. The file is named by the function set with WithFilenameFunc,
+// or after the last segment of the build's URL by default. Names that
+// would resolve outside dir are rejected. The file is removed if the
+// download fails.
+func (c *Client) DownloadBuildToDir(ctx context.Context, r Release, b Build, dir string) (string, error) {
+	target, err := c.buildPath(r, b, dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.DownloadBuild(ctx, b, f); err != nil {
+		f.Close()
+		os.Remove(target)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(target)
+		return "", err
+	}
+	return target, nil
+}
+
+// buildPath returns the path within dir that the artifact of b is
+// downloaded to, guarding against path traversal
+func (c *Client) buildPath(r Release, b Build, dir string) (string, error) {
+	name := b.Filename()
+	if c.filenameFunc != nil {
+		name = c.filenameFunc(r, b)
+	}
+	if name == "" {
+		return "", fmt.Errorf("no file name for build %s/%s of %s %s", b.OperatingSystem, b.Architecture, r.Name, r.Version)
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	target, ok := joinWithin(root, name)
+	if !ok || target == root || filepath.IsAbs(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("illegal download path %q", name)
+	}
+	return target, nil
+}
+
+// joinWithin joins the slash-separated path name to the absolute
+// directory root, reporting false if the result lies outside root
+func joinWithin(root, name string) (string, bool) {
+	target := filepath.Join(root, filepath.FromSlash(name))
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return target, true
+}
+
+// download issues the request and streams the body into w. Failed
+// attempts are retried as directed by the download retry policy,
+// resuming from the last byte received with a Range request.
+func (c *Client) download(req *http.Request, w io.Writer) (int64, error) {
+	var written int64
+	var validator string
+	for attempt := 1; ; attempt++ {
+		res, n, resumable, err := c.downloadFrom(req, w, written, &validator)
+		written += n
+		if err == nil {
+			return written, nil
+		}
+		if written > 0 && !resumable {
+			return written, err
+		}
+
+		// Consult the policy with the response for status errors and
+		// with the error otherwise
+		var retry bool
+		var delay time.Duration
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && res != nil {
+			retry, delay = c.downloadRetry.ShouldRetry(attempt, res, nil)
+		} else {
+			retry, delay = c.downloadRetry.ShouldRetry(attempt, nil, err)
+		}
+		if !retry {
+			return written, err
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			t.Stop()
+			return written, req.Context().Err()
+		}
+	}
+}
+
+// downloadFrom issues a single download attempt for the bytes of the
+// artifact from offset on, writing them to w. validator carries the
+// ETag or Last-Modified value of the first response, sent as If-Range
+// when resuming so that a changed artifact is not spliced together. It
+// reports whether the attempt may be resumed after a failure.
+func (c *Client) downloadFrom(req *http.Request, w io.Writer, offset int64, validator *string) (*http.Response, int64, bool, error) {
+	want := http.StatusOK
+	if offset > 0 {
+		req = req.Clone(req.Context())
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if *validator != "" {
+			req.Header.Set("If-Range", *validator)
+		}
+		want = http.StatusPartialContent
+	}
+	res, err := c.downloadClient().Do(req)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != want {
+		if offset > 0 && res.StatusCode == http.StatusOK {
+			return res, 0, false, fmt.Errorf("error resuming download of %s: server sent the whole artifact", req.URL)
+		}
+		apiErr := &APIError{StatusCode: res.StatusCode, URL: req.URL.String(), RequestID: responseRequestID(res)}
+		apiErr.readErrorBody(res.Body)
+		return res, 0, true, apiErr
+	}
+	if offset == 0 {
+		if *validator = res.Header.Get("ETag"); *validator == "" {
+			*validator = res.Header.Get("Last-Modified")
+		}
+	}
+
+	// Ranges apply to the encoded bytes, so a content-encoded artifact
+	// can only be retried from the start
+	resumable := !res.Uncompressed && res.Header.Get("Content-Encoding") == ""
+	var src io.Reader = res.Body
+	if c.stallTimeout > 0 {
+		stall := newStallReader(res.Body, c.stallTimeout)
+		defer stall.stop()
+		src = stall
+	}
+	raw := &countingReader{r: src}
+	body, err := decodedBody(res, raw)
+	if err != nil {
+		return res, 0, resumable, err
+	}
+	n, err := io.Copy(w, body)
+
+	// Catch truncation by comparing the bytes received with the
+	// advertised length, before the checksum is even verified
+	if res.ContentLength >= 0 && raw.n != res.ContentLength && (err == nil || errors.Is(err, io.ErrUnexpectedEOF)) {
+		return res, n, resumable, fmt.Errorf("%w: %s: expected %d bytes, got %d", ErrIncompleteDownload, req.URL, res.ContentLength, raw.n)
+	}
+	if err != nil {
+		return res, n, resumable, fmt.Errorf("error downloading %s: %w", req.URL, err)
+	}
+	return res, n, resumable, nil
+}
+
+// stallReader fails reads with ErrStalledDownload once no bytes have
+// been read from body for the timeout, closing body to unblock a read
+// in progress
+type stallReader struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled int32
+}
+
+func newStallReader(body io.ReadCloser, timeout time.Duration) *stallReader {
+	s := &stallReader{body: body, timeout: timeout}
+	s.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&s.stalled, 1)
+		body.Close()
+	})
+	return s
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+	if atomic.LoadInt32(&s.stalled) == 1 {
+		return n, fmt.Errorf("%w: no data received for %s", ErrStalledDownload, s.timeout)
+	}
+	if n > 0 {
+		s.timer.Reset(s.timeout)
+	}
+	return n, err
+}
+
+// stop releases the timer of the reader
+func (s *stallReader) stop() {
+	s.timer.Stop()
+}
+
+// downloadClient returns a copy of the client's HTTP client, sharing
+// its transport, with the API timeout replaced by the download timeout
+func (c *Client) downloadClient() *http.Client {
+	dl := *c.HTTPClient
+	dl.Timeout = c.downloadTimeout
+	return &dl
+}
+
+// decodedBody returns body, the body of res, decompressing it if the
+// server gzip-encoded it without the transport doing so transparently,
+// as happens when a CDN compresses unasked or Accept-Encoding was set
+// explicitly. Checksums must be computed over the decoded contents.
+func decodedBody(res *http.Response, body io.Reader) (io.Reader, error) {
+	if res.Uncompressed || res.Header.Get("Content-Encoding") != "gzip" {
+		return body, nil
+	}
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing %s: %w", res.Request.URL, err)
+	}
+	return gz, nil
+}
@@ -0,0 +1,40 @@
+package hashicorpreleases
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildPath(t *testing.T) {
+	r := Release{Name: "vault", Version: "1.15.0"}
+	b := Build{OperatingSystem: "linux", Architecture: "amd64"}
+	dir := t.TempDir()
+	tests := []struct {
+		dir, name, want string
+	}{
+		{dir, "vault.zip", filepath.Join(dir, "vault.zip")},
+		{dir, "1.15.0/vault.zip", filepath.Join(dir, "1.15.0", "vault.zip")},
+		{"/", "vault.zip", filepath.Join("/", "vault.zip")},
+		{dir, "../vault.zip", ""},
+		{dir, "1.15.0/../../vault.zip", ""},
+		{dir, "/etc/vault.zip", ""},
+		{dir, ".", ""},
+	}
+	for _, tt := range tests {
+		name := tt.name
+		c, err := NewClient(WithFilenameFunc(func(Release, Build) string { return name }))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		got, err := c.buildPath(r, b, tt.dir)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("buildPath(%q, %q) = %q, want an error", tt.dir, tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("buildPath(%q, %q) = %q, %v, want %q", tt.dir, tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package hashicorpreleases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrEmptyResponse is returned when the API responds with a 200 status
+// code but no body
+var ErrEmptyResponse = errors.New("empty response body")
+
+// ErrNotModified is returned by calls made with WithIfModifiedSince when
+// the resource has not changed
+var ErrNotModified = errors.New("not modified")
+
+// APIError is returned when the API responds with a non-200 status code
+type APIError struct {
+	// The HTTP status code of the response
+	StatusCode int
+	// The error message returned by the API, if it could be decoded
+	Message string
+	// The URL of the request
+	URL string
+	// The product requested, if any
+	Product string
+	// The version requested, if any
+	Version string
+	// The request ID reported by the API, if any
+	RequestID string
+	// The start of the raw response body, up to maxErrorBodySnippet
+	// bytes, if it was not a JSON error message, e.g. an HTML error page
+	// from a gateway
+	Body string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" && e.Body != "" {
+		return fmt.Sprintf("unknown error, status code: %d%s: %s", e.StatusCode, describeRequest(e.Product, e.Version, e.URL), e.Body)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("unknown error, status code: %d%s", e.StatusCode, describeRequest(e.Product, e.Version, e.URL))
+	}
+	return fmt.Sprintf("error: %s; status code: %d%s", e.Message, e.StatusCode, describeRequest(e.Product, e.Version, e.URL))
+}
+
+// maxErrorBodySnippet is how much of a non-JSON error response body is
+// kept in APIError.Body
+const maxErrorBodySnippet = 512
+
+// readErrorBody reads the body of an error response into e, as the
+// API's JSON error message if possible or as a snippet of the raw body
+func (e *APIError) readErrorBody(body io.Reader) {
+	raw, err := io.ReadAll(io.LimitReader(body, maxErrorBodySnippet))
+	if err != nil || len(raw) == 0 {
+		return
+	}
+	var errRes errorResponse
+	if err := json.Unmarshal(raw, &errRes); err == nil && errRes.Message != "" {
+		e.Message = errRes.Message
+		return
+	}
+	e.Body = strings.TrimSpace(strings.ToValidUTF8(string(raw), ""))
+}
+
+// DecodeError is returned when a successful response body cannot be
+// decoded, which usually indicates the API's schema has changed
+type DecodeError struct {
+	// The URL of the request
+	URL string
+	// The product requested, if any
+	Product string
+	// The version requested, if any
+	Version string
+	// The underlying decoding error
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error decoding response body%s: %s", describeRequest(e.Product, e.Version, e.URL), e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// withRequest records the product and version of the request that
+// produced an APIError or DecodeError, returning err
+func withRequest(err error, product, version string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Product, apiErr.Version = product, version
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Product, decodeErr.Version = product, version
+	}
+	return err
+}
+
+// describeRequest identifies a request for an error message by its
+// product and version, falling back to the URL path. Query parameters
+// are left out so messages stay stable regardless of the After cursor.
+func describeRequest(product, version, rawURL string) string {
+	switch {
+	case product != "" && version != "":
+		return fmt.Sprintf(" (product %s, version %s)", product, version)
+	case product != "":
+		return fmt.Sprintf(" (product %s)", product)
+	}
+	if u, err := url.Parse(rawURL); err == nil && u.Path != "" {
+		return fmt.Sprintf(" (%s)", u.Path)
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package hashicorpreleases
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ExtractBuild extracts a downloaded build archive to destDir, returning
+// the paths of the extracted files. See ExtractBuildReader.
+func ExtractBuild(zipPath string, destDir string) ([]string, error) {
+	f, err := os.Open(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ExtractBuildReader(f, info.Size(), destDir)
+}
+
+// ExtractBuildReader extracts a build's zip archive to destDir, creating
+// it if needed, and returns the paths of the extracted files. Entries
+// that would be written outside destDir are rejected, and file modes,
+// including the executable bit of the binary, are preserved.
+func ExtractBuildReader(r io.ReaderAt, size int64, destDir string) ([]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error reading build archive: %w", err)
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(destDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, f := range zr.File {
+
+		// Guard against zip-slip path traversal
+		target, ok := joinWithin(root, f.Name)
+		if !ok {
+			return files, fmt.Errorf("illegal path %q in build archive", f.Name)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return files, err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			return files, fmt.Errorf("unsupported file type %s for %q in build archive", f.Mode().Type(), f.Name)
+		}
+		if err := extractFile(f, target); err != nil {
+			return files, err
+		}
+		files = append(files, target)
+	}
+	return files, nil
+}
+
+// extractFile writes a single archive entry to target, preserving its
+// permission bits
+func extractFile(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("error reading %q from build archive: %w", f.Name, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("error extracting %q: %w", f.Name, err)
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	// The mode passed to OpenFile is subject to the umask and ignored
+	// for existing files, so apply it explicitly
+	return os.Chmod(target, f.Mode().Perm())
+}
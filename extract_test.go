@@ -0,0 +1,49 @@
+package hashicorpreleases
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildArchive returns a zip archive holding an empty file or directory
+// for each of names
+func buildArchive(t *testing.T, names ...string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestExtractBuildReaderPaths(t *testing.T) {
+	dir := t.TempDir()
+	archive := buildArchive(t, "./", "bin/", "bin/vault")
+	files, err := ExtractBuildReader(archive, archive.Size(), dir)
+	if err != nil {
+		t.Fatalf("ExtractBuildReader: %v", err)
+	}
+	want := filepath.Join(dir, "bin", "vault")
+	if len(files) != 1 || files[0] != want {
+		t.Errorf("got files %v, want [%s]", files, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{"../vault", "bin/../../vault"} {
+		archive := buildArchive(t, name)
+		if _, err := ExtractBuildReader(archive, archive.Size(), t.TempDir()); err == nil {
+			t.Errorf("got no error extracting %q", name)
+		}
+	}
+}
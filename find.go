@@ -0,0 +1,51 @@
+package hashicorpreleases
+
+// FindByVersion returns the release whose Version is exactly version. The
+// second return value reports whether one was found.
+func (r ReleasesResponse) FindByVersion(version string) (*Release, bool) {
+	for i := range r {
+		if r[i].Version == version {
+			return &r[i], true
+		}
+	}
+	return nil, false
+}
+
+// FindBySemver is like FindByVersion but compares versions semantically,
+// so "v1.15" matches a release versioned "1.15.0". Build metadata must
+// still match, so "1.15.0" does not match "1.15.0+ent". Releases with
+// unparseable versions are skipped.
+func (r ReleasesResponse) FindBySemver(version string) (*Release, bool) {
+	want, err := parseVersion(version)
+	if err != nil {
+		return nil, false
+	}
+	for i := range r {
+		v, err := parseVersion(r[i].Version)
+		if err == nil && v.compare(want) == 0 && v.metadata == want.metadata {
+			return &r[i], true
+		}
+	}
+	return nil, false
+}
+
+// Latest returns the newest release by creation time, regardless of the
+// order of r. The second return value is false if r is empty.
+func (r ReleasesResponse) Latest() (*Release, bool) {
+	var latest *Release
+	for i := range r {
+		if latest == nil || createdAt(r[i]).After(createdAt(*latest)) {
+			latest = &r[i]
+		}
+	}
+	return latest, latest != nil
+}
+
+// Versions returns the versions of the releases, in the order of r
+func (r ReleasesResponse) Versions() []string {
+	res := make([]string, 0, len(r))
+	for _, rel := range r {
+		res = append(res, rel.Version)
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+package hashicorpreleases_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	hashicorpreleases "github.com/rizkybiz/hashicorpreleases-go"
+	"github.com/rizkybiz/hashicorpreleases-go/releasestest"
+)
+
+// fixtureServer returns an httptest.Server serving the recorded fixtures
+// of the releasestest package, which is closed when the test ends. If
+// requestURIs is not nil, the request URI of each request is sent to it.
+func fixtureServer(t *testing.T, requestURIs chan<- string) *httptest.Server {
+	t.Helper()
+	fixtures := &releasestest.Transport{Fixtures: releasestest.Fixtures()}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestURIs != nil {
+			requestURIs <- r.RequestURI
+		}
+		res, err := fixtures.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer res.Body.Close()
+		w.Header().Set("X-Request-Id", r.URL.Path)
+		w.WriteHeader(res.StatusCode)
+		io.Copy(w, res.Body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetReleaseMetadataEnterpriseVersion(t *testing.T) {
+	requestURIs := make(chan string, 1)
+	srv := fixtureServer(t, requestURIs)
+	c, err := hashicorpreleases.NewClient(hashicorpreleases.WithBaseURL(srv.URL + "/v1"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	r, err := c.GetReleaseMetadata(context.Background(), "vault", "1.15.0+ent")
+	if err != nil {
+		t.Fatalf("GetReleaseMetadata: %v", err)
+	}
+	if got, want := <-requestURIs, "/v1/releases/vault/1.15.0%2Bent"; got != want {
+		t.Errorf("got request URI %s, want %s", got, want)
+	}
+	if r.Version != "1.15.0+ent" || r.LicenseClass != hashicorpreleases.LicenseEnterprise {
+		t.Errorf("got version %s with license class %s, want the enterprise release", r.Version, r.LicenseClass)
+	}
+}
+
+func TestFixturesArePaged(t *testing.T) {
+	c := releasestest.NewTestClient(releasestest.Fixtures())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.GetReleasesFiltered(ctx, "vault", &hashicorpreleases.ReleaseOptions{Limit: 2}, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("GetReleasesFiltered: %v", err)
+	}
+	var versions []string
+	for _, r := range res {
+		versions = append(versions, r.Version)
+	}
+	want := []string{"1.15.0", "1.15.0-rc1", "1.14.3", "1.13.7", "1.12.11"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("got versions %v, want %v", versions, want)
+	}
+}
+
+func TestStrictDecodingOfFixtures(t *testing.T) {
+	c := hashicorpreleases.MustNewClient(
+		hashicorpreleases.WithBaseURL("https://api.releases.hashicorp.com/v1"),
+		hashicorpreleases.WithHTTPClient(&http.Client{Transport: &releasestest.Transport{Fixtures: releasestest.Fixtures()}}),
+		hashicorpreleases.WithStrictDecoding(),
+	)
+	ctx := context.Background()
+
+	if _, err := c.GetProducts(ctx); err != nil {
+		t.Errorf("GetProducts: %v", err)
+	}
+	releases, err := c.GetReleases(ctx, "vault", nil)
+	if err != nil {
+		t.Errorf("GetReleases: %v", err)
+	}
+	for _, r := range releases {
+		if r.SourceRepositoryURL == "" {
+			t.Errorf("release %s has no source repository URL", r.Version)
+		}
+	}
+	for _, version := range []string{"1.15.0", "1.15.0+ent"} {
+		if _, err := c.GetReleaseMetadata(ctx, "vault", version); err != nil {
+			t.Errorf("GetReleaseMetadata %s: %v", version, err)
+		}
+	}
+}
+
+func TestSourceRepositoryURL(t *testing.T) {
+	c := releasestest.NewTestClient(releasestest.Fixtures())
+	ctx := context.Background()
+
+	want := map[string]string{
+		"1.15.0":     "https://github.com/hashicorp/vault",
+		"1.15.0+ent": "",
+	}
+	for version, url := range want {
+		r, err := c.GetReleaseMetadata(ctx, "vault", version)
+		if err != nil {
+			t.Fatalf("GetReleaseMetadata %s: %v", version, err)
+		}
+		if r.SourceRepositoryURL != url {
+			t.Errorf("got source repository URL %q for %s, want %q", r.SourceRepositoryURL, version, url)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package hashicorpreleases
+
+// GroupByMinor groups the releases by their "major.minor" version line,
+// e.g. "1.15". Prerelease and enterprise versions are grouped with their
+// line, releases whose version cannot be parsed are omitted, and each
+// group is sorted newest-first by creation time.
+func (r ReleasesResponse) GroupByMinor() map[string]ReleasesResponse {
+	groups := map[string]ReleasesResponse{}
+	for _, rel := range r {
+		v, err := parseVersion(rel.Version)
+		if err != nil {
+			continue
+		}
+		key := v.minorLine()
+		groups[key] = append(groups[key], rel)
+	}
+	for _, g := range groups {
+		g.SortByCreated(false)
+	}
+	return groups
+}
+
+// LatestPerMinor returns the newest release, by semantic version, of
+// each "major.minor" version line, so 1.15.10 is chosen over 1.15.9.
+// Prereleases are excluded; see LatestPerMinorWithPrereleases. The
+// result is sorted by version, newest line first.
+func (r ReleasesResponse) LatestPerMinor() ReleasesResponse {
+	return r.latestPerMinor(false)
+}
+
+// LatestPerMinorWithPrereleases is LatestPerMinor with prereleases
+// taken into account
+func (r ReleasesResponse) LatestPerMinorWithPrereleases() ReleasesResponse {
+	return r.latestPerMinor(true)
+}
+
+func (r ReleasesResponse) latestPerMinor(includePrereleases bool) ReleasesResponse {
+	res := ReleasesResponse{}
+	for _, g := range r.GroupByMinor() {
+		if !includePrereleases {
+			g = g.stable()
+		}
+		if len(g) == 0 {
+			continue
+		}
+		g.SortByVersion(false)
+		res = append(res, g[0])
+	}
+	res.SortByVersion(false)
+	return res
+}
+
+// stable returns the releases that are not prereleases
+func (r ReleasesResponse) stable() ReleasesResponse {
+	res := ReleasesResponse{}
+	for _, rel := range r {
+		if !isPrerelease(rel) {
+			res = append(res, rel)
+		}
+	}
+	return res
+}
+
+// isPrerelease reports whether a release is flagged as a prerelease
+// or has a prerelease version such as "1.15.0-rc1"
+func isPrerelease(r Release) bool {
+	if r.IsPrerelease {
+		return true
+	}
+	v, err := parseVersion(r.Version)
+	return err == nil && v.prerelease != ""
+}
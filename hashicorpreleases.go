@@ -1,18 +1,60 @@
 package hashicorpreleases
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 )
 
+// requestIDHeaders are the response headers, in order of preference,
+// that may carry an identifier of the request for the API operators
+var requestIDHeaders = []string{"X-Request-Id", "X-Amz-Request-Id", "X-Amz-Cf-Id"}
+
 // Client represents an HTTP client for interfacing with the
-// HashiCorp Releases API
+// HashiCorp Releases API. A Client is safe for concurrent use by
+// multiple goroutines once created; its caches, circuit breaker,
+// concurrency limit and request ID are guarded internally. URL and
+// HTTPClient must not be modified after the client is first used.
 type Client struct {
 	URL        string
 	HTTPClient *http.Client
+
+	host                string
+	apiVersion          string
+	requireAPIVersion   string
+	defaultLimit        int
+	defaultLicenseClass LicenseClass
+	optionsFromContext  func(context.Context) *ReleaseOptions
+	accept              string
+	getContentType      bool
+	now                 func() time.Time
+	products            productsCache
+	signingKey          signingKeyCache
+	strictDecoding      bool
+	decode              DecodeFunc
+	downloadTimeout     time.Duration
+	stallTimeout        time.Duration
+	filenameFunc        func(Release, Build) string
+	recordDir           string
+	breaker             *circuitBreaker
+	sem                 chan struct{}
+	headers             http.Header
+	retryPolicy         RetryPolicy
+	downloadRetry       RetryPolicy
+	metrics             Collector
+	logger              Logger
+
+	mu            sync.Mutex
+	lastRequestID string
 }
 
 type errorResponse struct {
@@ -20,57 +62,339 @@ type errorResponse struct {
 	Message string `json:"message"`
 }
 
-// NewClient returns a new hashicorpreleases client. Provide a
-// custom releases endpoint by setting RELEASES_URL in the
-// environment
-func NewClient() *Client {
+// jsonContentType is the media type of the API's requests and
+// responses, used as the default Accept header
+const jsonContentType = "application/json; charset=utf-8"
+
+// defaultHost and defaultAPIVersion make up the default base URL of the
+// releases API
+const (
+	defaultHost       = "https://api.releases.hashicorp.com"
+	defaultAPIVersion = "v1"
+)
+
+// defaultTimeout is the timeout applied to requests against the API.
+// Artifact downloads are not subject to it.
+const defaultTimeout = 30 * time.Second
+
+// defaultMaxRedirects is the number of redirects followed by default,
+// e.g. from an artifact URL to a CDN
+const defaultMaxRedirects = 10
+
+// limitRedirects is the default redirect policy, which stops after
+// defaultMaxRedirects redirects
+func limitRedirects(req *http.Request, via []*http.Request) error {
+	if len(via) >= defaultMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+	}
+	return nil
+}
+
+// NewClient returns a new hashicorpreleases client configured
+// with the provided options. Provide a custom releases endpoint
+// by setting RELEASES_URL in the environment. An error is returned
+// if an option or the releases endpoint is invalid.
+func NewClient(opts ...ClientOption) (*Client, error) {
 
 	// Check if a URL is provided via ENV VARS
-	url := os.Getenv("RELEASES_URL")
-	if url == "" {
-		url = "https://api.releases.hashicorp.com/v1"
+	baseURL := os.Getenv("RELEASES_URL")
+	if baseURL == "" {
+		baseURL = defaultHost + "/" + defaultAPIVersion
 	}
 
-	// Setup the client and return
-	return &Client{
-		URL: url,
+	// Setup the client
+	c := &Client{
+		URL: baseURL,
 		HTTPClient: &http.Client{
-			Timeout: time.Minute * 1,
+			Timeout:       defaultTimeout,
+			CheckRedirect: limitRedirects,
 		},
+		defaultLimit: defaultLimit,
+		now:          time.Now,
+		products: productsCache{
+			interval: defaultProductsRefreshInterval,
+		},
+		signingKey: signingKeyCache{
+			url: defaultSigningKeyURL,
+		},
+		retryPolicy:   NoRetry{},
+		downloadRetry: NoRetry{},
+		accept:        jsonContentType,
+	}
+
+	// Apply the options
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compose the base URL from its parts if either was set
+	if c.host != "" || c.apiVersion != "" {
+		host, version := c.host, c.apiVersion
+		if host == "" {
+			host = defaultHost
+		}
+		if version == "" {
+			version = defaultAPIVersion
+		}
+		c.URL = host + "/" + version
+	}
+
+	// Normalize the base URL and return
+	u, err := normalizeBaseURL(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	c.URL = u
+	if err := c.checkAPIVersion(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// MustNewClient is like NewClient but panics if the client cannot be
+// created, e.g. because RELEASES_URL is invalid
+func MustNewClient(opts ...ClientOption) *Client {
+	c, err := NewClient(opts...)
+	if err != nil {
+		panic(err)
 	}
+	return c
 }
 
-// sendRequest assumes proper "content-type" header is set
-// and that a body is attached if necessary to the http request
-func (c *Client) sendRequest(req *http.Request, v interface{}) error {
+// NewVerifiedClient is like NewClient but also probes the releases API
+// with APIInfo before returning the client, failing fast if it is
+// unreachable or, when WithRequireAPIVersion is used, does not serve the
+// required version. The probe respects the deadline of ctx.
+func NewVerifiedClient(ctx context.Context, opts ...ClientOption) (*Client, error) {
+	c, err := NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.APIInfo(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// normalizeBaseURL validates that a base URL is absolute and trims any
+// trailing slashes so that endpoint paths can be appended to it
+func normalizeBaseURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+	return strings.TrimRight(baseURL, "/"), nil
+}
+
+// Ping checks that the releases API is reachable by requesting the
+// product list. It returns nil if the API responds with a 200 and
+// respects the deadline of the provided context.
+func (c *Client) Ping(ctx context.Context) error {
+
+	// Request the product list
+	if _, err := c.GetProducts(ctx); err != nil {
+		return fmt.Errorf("can't reach releases API at %s: %w", c.URL, err)
+	}
+	return nil
+}
+
+// Do issues a prebuilt request against the releases API, e.g. for an
+// endpoint this library has no method for yet, and decodes the JSON
+// response into v. The request goes through the same pipeline as the
+// typed methods: default and call headers, the concurrency limit, the
+// circuit breaker, retries and error handling, returning an APIError for
+// non-200 responses. A request with a body is only retried if its
+// GetBody is set, as it is by http.NewRequest for common body types.
+func (c *Client) Do(req *http.Request, v interface{}, opts ...CallOption) error {
+	if req == nil {
+		return fmt.Errorf("request must not be nil")
+	}
+	return c.sendRequest(req, v, opts...)
+}
+
+// sendRequest assumes a body is attached if necessary to the
+// http request and sets the JSON headers accordingly
+func (c *Client) sendRequest(req *http.Request, v interface{}, opts ...CallOption) error {
+
+	// Merge the call options over the client's configuration
+	call := c.callConfig(opts)
+	if call.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), call.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	// Wait for an in-flight request slot
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if c.breaker == nil {
+		return c.doRequest(req, v, call)
+	}
+
+	// Short-circuit while the breaker is open and record the outcome
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err = c.doRequest(req, v, call)
+	c.breaker.record(err)
+	return err
+}
+
+// acquire waits for a slot under the limit set by WithMaxConcurrency,
+// returning a function that releases it. It returns ctx.Err() if ctx
+// is done before a slot becomes available.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.sem <- struct{}{}:
+		return func() { <-c.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// doRequest issues the request and decodes the response into v
+func (c *Client) doRequest(req *http.Request, v interface{}, call callConfig) (err error) {
 
 	// Set the appropriate headers
-	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("Accept", c.accept)
+	c.setJSONHeader(req)
+	c.setHeaders(req)
+	if !call.ifModifiedSince.IsZero() {
+		req.Header.Set("If-Modified-Since", call.ifModifiedSince.UTC().Format(http.TimeFormat))
+	}
+	for k, vals := range call.headers {
+		req.Header[k] = append([]string(nil), vals...)
+	}
 
-	// execute the http request
-	res, err := c.HTTPClient.Do(req)
+	// execute the http request, with the call timeout replacing the
+	// API timeout if one is set
+	hc := c.HTTPClient
+	if call.timeout > 0 {
+		hc = c.callClient(call.timeout)
+	}
+	res, err := c.do(hc, req, call.retryPolicy)
 	if err != nil {
+		c.observeRequest(0, 0, err)
 		return err
 	}
 	defer res.Body.Close()
+	counted, read := c.countBody(res.Body)
+	defer func() { c.observeRequest(res.StatusCode, read(), err) }()
 
 	// Check for non OK status code and attempt to decode into errorResponse
+	requestID := responseRequestID(res)
+	if res.StatusCode == http.StatusNotModified && !call.ifModifiedSince.IsZero() {
+		return ErrNotModified
+	}
 	if res.StatusCode != http.StatusOK {
-		var errRes errorResponse
-		if err = json.NewDecoder(res.Body).Decode(&errRes); err == nil {
-			return fmt.Errorf("error: %s; status code: %d", errRes.Message, res.StatusCode)
+		apiErr := &APIError{StatusCode: res.StatusCode, URL: req.URL.String(), RequestID: requestID}
+		apiErr.readErrorBody(counted)
+		return apiErr
+	}
+	c.mu.Lock()
+	c.lastRequestID = requestID
+	c.mu.Unlock()
+
+	// Tee the body into the response recorder, if configured
+	if rec := c.record(req.URL); rec != nil {
+		counted = io.TeeReader(counted, rec)
+		defer c.finishRecording(rec, counted)
+	}
+
+	// Decode with encoding/json straight from the body, which reports an
+	// empty body as io.EOF, avoiding a second layer of buffering
+	if c.decode == nil {
+		err = c.decodeJSON(counted, v)
+		if err == io.EOF {
+			return fmt.Errorf("%w from %s", ErrEmptyResponse, req.URL)
 		}
-		return fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+		if err != nil {
+			return &DecodeError{URL: req.URL.String(), Err: err}
+		}
+		return nil
 	}
 
-	// Attempt to decode response into whichever interface was provided
-	err = json.NewDecoder(res.Body).Decode(&v)
+	// Custom decoders may not report an empty body, so detect it first,
+	// as it would otherwise surface as a bare EOF
+	body := bufio.NewReader(counted)
+	if _, err := body.Peek(1); err == io.EOF {
+		return fmt.Errorf("%w from %s", ErrEmptyResponse, req.URL)
+	}
+	err = c.decode(body, v)
 	if err != nil {
-		return fmt.Errorf("error decoding response body: %s", err)
+		return &DecodeError{URL: req.URL.String(), Err: err}
 	}
 	return nil
 }
 
-func setJSONHeader(r *http.Request) {
-	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+// callClient returns a copy of the client's HTTP client, sharing its
+// transport, with the API timeout replaced by a call timeout
+func (c *Client) callClient(timeout time.Duration) *http.Client {
+	hc := *c.HTTPClient
+	hc.Timeout = timeout
+	return &hc
+}
+
+// decodeJSON is the default DecodeFunc, decoding with encoding/json and
+// rejecting unknown fields if strict decoding is enabled
+func (c *Client) decodeJSON(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	if !c.strictDecoding {
+		return dec.Decode(v)
+	}
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	return statusUnknownFields(reflect.ValueOf(v))
+}
+
+// LastRequestID returns the request ID reported by the API for the most
+// recent successful request, for use in support tickets. Failed requests
+// report theirs in APIError.RequestID. It is empty if the API did not
+// send one.
+func (c *Client) LastRequestID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastRequestID
+}
+
+// responseRequestID returns the request ID header of a response, if any
+func responseRequestID(res *http.Response) string {
+	for _, h := range requestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// setHeaders sets the User-Agent and applies the headers configured with
+// WithHeaders, replacing any values already set on the request
+func (c *Client) setHeaders(r *http.Request) {
+	r.Header.Set("User-Agent", userAgent())
+	for k, v := range c.headers {
+		r.Header[k] = append([]string(nil), v...)
+	}
+}
+
+// setJSONHeader sets the JSON Content-Type header on requests that have
+// a body, or on GET requests if enabled with WithGETContentType
+func (c *Client) setJSONHeader(r *http.Request) {
+	hasBody := r.Body != nil && r.Body != http.NoBody
+	if hasBody || (r.Method == http.MethodGet && c.getContentType) {
+		r.Header.Set("Content-Type", jsonContentType)
+	}
 }
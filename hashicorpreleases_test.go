@@ -0,0 +1,99 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose base URL points at an
+// httptest.Server serving h, which is closed when the test ends
+func newTestClient(t *testing.T, h http.Handler, opts ...ClientOption) (*Client, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts = append([]ClientOption{WithBaseURL(srv.URL + "/v1")}, opts...)
+	c, err := NewClient(opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c, srv
+}
+
+func TestCallTimeoutReplacesAPITimeout(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte(`["vault"]`))
+	}), WithAPITimeout(100*time.Millisecond))
+	ctx := context.Background()
+
+	if _, err := c.GetProducts(ctx); err == nil {
+		t.Fatal("expected the API timeout to cancel the request")
+	}
+	if _, err := c.GetProducts(ctx, WithCallTimeout(5*time.Second)); err != nil {
+		t.Fatalf("got %v, want a longer call timeout to let the request finish", err)
+	}
+	c.HTTPClient.Timeout = 5 * time.Second
+	if _, err := c.GetProducts(ctx, WithCallTimeout(100*time.Millisecond)); err == nil {
+		t.Fatal("expected a shorter call timeout to cancel the request")
+	}
+}
+
+func TestEmptyResponse(t *testing.T) {
+	empty := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	decoders := map[string][]ClientOption{
+		"default": nil,
+		"custom": {WithDecoder(func(r io.Reader, v interface{}) error {
+			return json.NewDecoder(r).Decode(v)
+		})},
+	}
+	for name, opts := range decoders {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestClient(t, empty, opts...)
+			_, err := c.GetProducts(context.Background())
+			if !errors.Is(err, ErrEmptyResponse) {
+				t.Fatalf("got %v, want ErrEmptyResponse", err)
+			}
+		})
+	}
+}
+
+func TestBaseURLTrailingSlash(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`["vault"]`))
+	}))
+	defer srv.Close()
+
+	newClients := map[string]func() (*Client, error){
+		"RELEASES_URL": func() (*Client, error) {
+			t.Setenv("RELEASES_URL", srv.URL+"/mirror/v1/")
+			return NewClient()
+		},
+		"WithBaseURL": func() (*Client, error) {
+			return NewClient(WithBaseURL(srv.URL + "/mirror/v1//"))
+		},
+	}
+	for name, newClient := range newClients {
+		t.Run(name, func(t *testing.T) {
+			c, err := newClient()
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if _, err := c.GetProducts(context.Background()); err != nil {
+				t.Fatalf("GetProducts: %v", err)
+			}
+			if path != "/mirror/v1/products" {
+				t.Errorf("got request path %s, want /mirror/v1/products", path)
+			}
+		})
+	}
+}
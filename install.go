@@ -0,0 +1,118 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrNoMatchingRelease is returned by ResolveInstall when no release
+// satisfies the install policy
+var ErrNoMatchingRelease = errors.New("no release matches the install policy")
+
+// InstallPolicy bundles the criteria ResolveInstall selects a release by
+type InstallPolicy struct {
+	// Constraint restricts the version, e.g. ">= 1.14, < 1.16" or
+	// "~> 1.15". Any version is allowed if it is empty.
+	Constraint string
+	// AllowPrerelease allows prereleases to be selected
+	AllowPrerelease bool
+	// AllowWithdrawn allows withdrawn releases to be selected
+	AllowWithdrawn bool
+	// OS and Arch select the build, defaulting to the platform of the
+	// running program
+	OS   OS
+	Arch Arch
+}
+
+// ResolveInstall pages through the releases of a product and returns the
+// release with the highest version satisfying policy, along with its
+// build for the policy's platform. Backported patches of older lines are
+// interleaved with newer releases, so paging only stops at the first
+// page holding no release newer than the best match so far. If nothing
+// matches, an error wrapping ErrNoMatchingRelease counts the releases
+// rejected by each criterion.
+func (c *Client) ResolveInstall(ctx context.Context, product string, policy InstallPolicy) (*Release, *Build, error) {
+	var cs constraints
+	if policy.Constraint != "" {
+		var err error
+		if cs, err = parseConstraints(policy.Constraint); err != nil {
+			return nil, nil, err
+		}
+	}
+	os, arch := policy.OS, policy.Arch
+	if os == "" {
+		os = OS(runtime.GOOS)
+	}
+	if arch == "" {
+		arch = Arch(runtime.GOARCH)
+	}
+
+	var (
+		best                   *Release
+		bestBuild              *Build
+		bestVersion            semver
+		prereleases, withdrawn int
+		outside, unbuilt       int
+		unpublished            int
+	)
+	err := c.pageReleases(ctx, product, &ReleaseOptions{Limit: maxLimit}, func(page ReleasesResponse) error {
+		newer := false
+		for i := range page {
+			r := page[i]
+			v, err := parseVersion(r.Version)
+			if err != nil {
+				continue
+			}
+			if best != nil && v.compare(bestVersion) <= 0 {
+				continue
+			}
+			newer = true
+			switch {
+			case !policy.AllowPrerelease && isPrerelease(r):
+				prereleases++
+			case !policy.AllowWithdrawn && r.Withdrawal().Withdrawn:
+				withdrawn++
+			case cs != nil && !cs.check(v):
+				outside++
+			case !r.HasBuilds():
+				unpublished++
+			default:
+				b, ok := r.Build(os, arch)
+				if !ok {
+					unbuilt++
+					continue
+				}
+				best, bestBuild, bestVersion = &r, b, v
+			}
+		}
+		if best != nil && !newer {
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if best == nil {
+		reasons := []string{}
+		add := func(n int, reason string) {
+			if n > 0 {
+				reasons = append(reasons, fmt.Sprintf("%d %s", n, reason))
+			}
+		}
+		add(prereleases, "prereleases")
+		add(withdrawn, "withdrawn")
+		add(outside, "outside the version constraint")
+		add(unpublished, "without any builds")
+		add(unbuilt, fmt.Sprintf("without a %s/%s build", os, arch))
+		if len(reasons) == 0 {
+			return nil, nil, fmt.Errorf("%w for %s: no releases", ErrNoMatchingRelease, product)
+		}
+		return nil, nil, fmt.Errorf("%w for %s: rejected %s", ErrNoMatchingRelease, product, strings.Join(reasons, ", "))
+	}
+	c.warnBuild(*best, *bestBuild)
+	return best, bestBuild, nil
+}
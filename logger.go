@@ -0,0 +1,32 @@
+package hashicorpreleases
+
+// Logger receives warnings about risky selections, such as withdrawn
+// releases and unsupported builds, e.g. to forward them to the
+// application's logger. Implementations must be safe for concurrent use.
+type Logger interface {
+	// Warnf logs a warning-level message formatted like fmt.Printf
+	Warnf(format string, args ...interface{})
+}
+
+// warnf logs a warning to the logger, if configured
+func (c *Client) warnf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Warnf(format, args...)
+	}
+}
+
+// warnWithdrawn logs a warning if r has been withdrawn
+func (c *Client) warnWithdrawn(r Release) {
+	if w := r.Withdrawal(); w.Withdrawn {
+		c.warnf("%s %s has been withdrawn: %s", r.Name, r.Version, w.Message)
+	}
+}
+
+// warnBuild logs a warning if r has been withdrawn or b, one of its
+// builds, is not supported by HashiCorp
+func (c *Client) warnBuild(r Release, b Build) {
+	c.warnWithdrawn(r)
+	if b.Unsupported {
+		c.warnf("%s %s build for %s/%s is not supported by HashiCorp", r.Name, r.Version, b.OperatingSystem, b.Architecture)
+	}
+}
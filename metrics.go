@@ -0,0 +1,52 @@
+package hashicorpreleases
+
+import "io"
+
+// Collector receives metrics about the traffic of a client, e.g. to
+// export them to Prometheus. Implementations must be safe for concurrent
+// use.
+type Collector interface {
+	// ObserveRequest is called once per API request, including fetches
+	// of SHASUMS and signature files, after retries. statusCode is 0 if
+	// no response was received and bytes is the size of the body read.
+	ObserveRequest(statusCode int, bytes int64, err error)
+	// ObserveDownload is called once per artifact download with the
+	// number of bytes downloaded.
+	ObserveDownload(bytes int64, err error)
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countBody wraps the body if a collector is configured, returning
+// a function that reports the bytes read from it
+func (c *Client) countBody(body io.Reader) (io.Reader, func() int64) {
+	if c.metrics == nil {
+		return body, func() int64 { return 0 }
+	}
+	counter := &countingReader{r: body}
+	return counter, func() int64 { return counter.n }
+}
+
+// observeRequest reports a request to the collector, if configured
+func (c *Client) observeRequest(statusCode int, bytes int64, err error) {
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(statusCode, bytes, err)
+	}
+}
+
+// observeDownload reports a download to the collector, if configured
+func (c *Client) observeDownload(bytes int64, err error) {
+	if c.metrics != nil {
+		c.metrics.ObserveDownload(bytes, err)
+	}
+}
@@ -0,0 +1,538 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient
+type ClientOption func(*Client) error
+
+// WithDefaultLimit sets the number of results returned by GetReleases
+// whenever ReleaseOptions.Limit is unset. A Limit set on the call still
+// takes precedence. The limit must be between 1 and 20.
+func WithDefaultLimit(limit int) ClientOption {
+	return func(c *Client) error {
+		if limit < 1 || limit > maxLimit {
+			return fmt.Errorf("default limit must be between 1 and %d, got %d", maxLimit, limit)
+		}
+		c.defaultLimit = limit
+		return nil
+	}
+}
+
+// WithClock sets the function used to obtain the current time, which
+// GetReleases uses as the default After cursor. It defaults to time.Now
+// and can be fixed for deterministic tests or "as-of" queries. It does
+// not affect the circuit breaker's cooldown or the product cache's age,
+// which are always measured in wall-clock time.
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) error {
+		if now == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+		c.now = now
+		return nil
+	}
+}
+
+// WithProductsRefreshInterval sets how long Products serves the cached
+// product list before fetching it again. It defaults to one hour.
+func WithProductsRefreshInterval(interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return fmt.Errorf("products refresh interval must be positive, got %s", interval)
+		}
+		c.products.interval = interval
+		return nil
+	}
+}
+
+// WithStrictDecoding makes the client reject responses containing fields
+// that are not part of this library's types, returning a DecodeError.
+// It is intended for tests and monitoring as a canary for upstream
+// schema drift, not for production use, where new fields added by the
+// API would otherwise turn into failed requests.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) error {
+		c.strictDecoding = true
+		return nil
+	}
+}
+
+// DecodeFunc decodes a successful response body from r into v, which is
+// a pointer to one of this library's response types
+type DecodeFunc func(r io.Reader, v interface{}) error
+
+// WithDecoder replaces the encoding/json decoding of successful API
+// responses, e.g. to use a different JSON library. Errors returned by
+// decode are wrapped in a DecodeError. WithStrictDecoding has no effect
+// on a custom decoder; it must reject unknown fields itself if desired.
+func WithDecoder(decode DecodeFunc) ClientOption {
+	return func(c *Client) error {
+		if decode == nil {
+			return fmt.Errorf("decoder must not be nil")
+		}
+		c.decode = decode
+		return nil
+	}
+}
+
+// WithAPITimeout sets the timeout applied to each request against the
+// releases API. It defaults to 30 seconds and does not apply to artifact
+// downloads. A timeout of 0 disables the client-level timeout so that
+// requests are bounded solely by their context. When both are set they
+// are independent and whichever expires first cancels the request.
+func WithAPITimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("API timeout must not be negative, got %s", timeout)
+		}
+		c.HTTPClient.Timeout = timeout
+		return nil
+	}
+}
+
+// WithDownloadTimeout sets an overall timeout for artifact downloads.
+// By default downloads have no fixed timeout and are bounded only by the
+// context passed to the download helpers; a timeout of 0 restores that.
+func WithDownloadTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("download timeout must not be negative, got %s", timeout)
+		}
+		c.downloadTimeout = timeout
+		return nil
+	}
+}
+
+// WithDownloadStallTimeout aborts an artifact download with
+// ErrStalledDownload once no bytes have been received for timeout, even
+// though the connection is still open. Unlike WithDownloadTimeout it
+// does not bound large downloads that are progressing slowly. By
+// default downloads are not checked for stalls; a timeout of 0 restores
+// that. A stalled download is retried if WithDownloadRetry allows it.
+func WithDownloadStallTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("download stall timeout must not be negative, got %s", timeout)
+		}
+		c.stallTimeout = timeout
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all requests. A shallow
+// copy of the client is stored, so options applied afterwards, such as
+// WithProxy or WithAPITimeout, do not modify the caller's client.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) error {
+		if client == nil {
+			return fmt.Errorf("HTTP client must not be nil")
+		}
+		hc := *client
+		c.HTTPClient = &hc
+		return nil
+	}
+}
+
+// WithRedirectPolicy sets the policy deciding whether a redirect is
+// followed, for both API requests and artifact downloads, as
+// http.Client.CheckRedirect does, e.g. to refuse redirects to other
+// hosts. By default at most 10 redirects are followed. A client passed
+// to WithHTTPClient keeps its own CheckRedirect, and replaces a policy
+// set before it, so apply WithRedirectPolicy after WithHTTPClient.
+func WithRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) ClientOption {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("redirect policy must not be nil")
+		}
+		c.HTTPClient.CheckRedirect = policy
+		return nil
+	}
+}
+
+// WithFilenameFunc sets the function naming the files written by
+// DownloadBuildToDir, e.g. to lay out a mirror in versioned directories.
+// The name is a slash-separated path relative to the destination
+// directory; paths resolving outside it are rejected. By default files
+// are named after the last segment of the build's URL.
+func WithFilenameFunc(fn func(Release, Build) string) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("filename function must not be nil")
+		}
+		c.filenameFunc = fn
+		return nil
+	}
+}
+
+// WithProxy routes all requests through the proxy at proxyURL
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *Client) error {
+		if proxyURL == nil {
+			return fmt.Errorf("proxy URL must not be nil")
+		}
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for all requests,
+// e.g. to trust a custom certificate authority
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) error {
+		if config == nil {
+			return fmt.Errorf("TLS config must not be nil")
+		}
+		tr, err := c.transport()
+		if err != nil {
+			return err
+		}
+		tr.TLSClientConfig = config.Clone()
+		return nil
+	}
+}
+
+// transport installs a copy of the HTTP client's transport, or of
+// http.DefaultTransport if none is set, so that it can be configured
+// without affecting transports shared with other clients
+func (c *Client) transport() (*http.Transport, error) {
+	var tr *http.Transport
+	switch t := c.HTTPClient.Transport.(type) {
+	case nil:
+		tr = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		tr = t.Clone()
+	default:
+		return nil, fmt.Errorf("unable to configure HTTP transport of type %T", t)
+	}
+	c.HTTPClient.Transport = tr
+	return tr, nil
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen once
+// threshold consecutive requests have failed with a transport error or a
+// 5xx response. After cooldown a single trial request is let through;
+// the breaker closes again if it succeeds and reopens if it fails.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) error {
+		if threshold < 1 {
+			return fmt.Errorf("circuit breaker threshold must be at least 1, got %d", threshold)
+		}
+		if cooldown <= 0 {
+			return fmt.Errorf("circuit breaker cooldown must be positive, got %s", cooldown)
+		}
+		c.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+			now:       time.Now,
+		}
+		return nil
+	}
+}
+
+// WithMaxConcurrency bounds the number of requests the client has in
+// flight at once, across all goroutines sharing it. Requests beyond the
+// limit wait for a slot, or fail with the context's error if their
+// context is done first.
+func WithMaxConcurrency(n int) ClientOption {
+	return func(c *Client) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrency must be at least 1, got %d", n)
+		}
+		c.sem = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithHeaders sets headers that are added to every request the client
+// makes, including artifact downloads. They are applied last, so a
+// header configured here takes precedence over one set by the library,
+// such as Accept or Content-Type.
+func WithHeaders(headers http.Header) ClientOption {
+	return func(c *Client) error {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		for k, v := range headers {
+			c.headers[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy consulted to decide whether, and after
+// what delay, a failed API request is retried. It defaults to NoRetry.
+// It does not apply to artifact downloads; see WithDownloadRetry.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy must not be nil")
+		}
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithDownloadRetry sets the policy consulted to decide whether, and
+// after what delay, a failed artifact download is retried. Retries
+// resume from the last byte received using a Range request rather than
+// starting over, unless the server content-encoded the artifact or sent
+// it whole when asked for a range. It defaults to NoRetry, independently
+// of WithRetryPolicy.
+func WithDownloadRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("download retry policy must not be nil")
+		}
+		c.downloadRetry = policy
+		return nil
+	}
+}
+
+// CallOption overrides the client's configuration for a single call
+type CallOption func(*callConfig)
+
+// callConfig is the configuration of a single call
+type callConfig struct {
+	timeout         time.Duration
+	headers         http.Header
+	retryPolicy     RetryPolicy
+	ifModifiedSince time.Time
+}
+
+// callConfig merges the call options over the client's configuration
+func (c *Client) callConfig(opts []CallOption) callConfig {
+	call := callConfig{retryPolicy: c.retryPolicy}
+	for _, opt := range opts {
+		opt(&call)
+	}
+	return call
+}
+
+// WithCallTimeout bounds the duration of a single call, including any
+// retries. It replaces the client's API timeout for the call, so it may
+// be longer than it, e.g. for a product known to be slow to respond.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(call *callConfig) {
+		call.timeout = timeout
+	}
+}
+
+// WithCallHeaders sets headers for a single call. They take precedence
+// over both the library's headers and those set with WithHeaders.
+func WithCallHeaders(headers http.Header) CallOption {
+	return func(call *callConfig) {
+		if call.headers == nil {
+			call.headers = http.Header{}
+		}
+		for k, v := range headers {
+			call.headers[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+	}
+}
+
+// WithCallRetryPolicy overrides the client's retry policy for a single
+// call. A nil policy is ignored.
+func WithCallRetryPolicy(policy RetryPolicy) CallOption {
+	return func(call *callConfig) {
+		if policy != nil {
+			call.retryPolicy = policy
+		}
+	}
+}
+
+// WithMetrics reports the client's requests and downloads to collector.
+// No metrics are gathered when no collector is configured.
+func WithMetrics(collector Collector) ClientOption {
+	return func(c *Client) error {
+		if collector == nil {
+			return fmt.Errorf("metrics collector must not be nil")
+		}
+		c.metrics = collector
+		return nil
+	}
+}
+
+// WithSigningKeyURL sets the URL GetSigningKey fetches HashiCorp's
+// public key from, e.g. a copy hosted alongside a self-hosted mirror
+func WithSigningKeyURL(keyURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(keyURL)
+		if err != nil {
+			return fmt.Errorf("invalid signing key URL %q: %w", keyURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid signing key URL %q: scheme and host are required", keyURL)
+		}
+		c.signingKey.url = keyURL
+		return nil
+	}
+}
+
+// WithResponseRecorder saves the body of each successful API response
+// into dir, under its URL path with a ".json" extension, e.g.
+// "v1/releases/vault/1.15.0.json", which is the layout the releasestest
+// Transport serves fixtures from. Query strings are ignored, so later
+// pages of the same path overwrite earlier ones. Bodies are recorded as
+// they are decoded, and failures to record are logged rather than
+// failing the request.
+func WithResponseRecorder(dir string) ClientOption {
+	return func(c *Client) error {
+		if dir == "" {
+			return fmt.Errorf("response recorder directory must not be empty")
+		}
+		c.recordDir = dir
+		return nil
+	}
+}
+
+// WithLogger sends warnings about withdrawn releases and unsupported
+// builds encountered by GetReleaseMetadata, BuildURLsForPlatform and
+// DownloadBuild to logger. Nothing is logged when no logger is
+// configured.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithDefaultLicenseClass sets the license class, LicenseEnterprise or
+// LicenseOSS, used whenever ReleaseOptions.LicenseClass is unset. A
+// LicenseClass set on the call still takes precedence.
+func WithDefaultLicenseClass(licenseClass LicenseClass) ClientOption {
+	return func(c *Client) error {
+		if err := validateLicenseClass(licenseClass); err != nil {
+			return err
+		}
+		c.defaultLicenseClass = licenseClass
+		return nil
+	}
+}
+
+// WithOptionsFromContext sets a function deriving default ReleaseOptions
+// from the context of each call, e.g. a tenant's license class in a
+// multi-tenant service. Fields set in the options passed to a call take
+// precedence over those derived from the context, which in turn take
+// precedence over the client's defaults. fn may return nil.
+func WithOptionsFromContext(fn func(ctx context.Context) *ReleaseOptions) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("options function must not be nil")
+		}
+		c.optionsFromContext = fn
+		return nil
+	}
+}
+
+// WithBaseURL sets the base URL of the releases API, including the API
+// version path, e.g. "https://mirror.example.com/releases/v1". It takes
+// precedence over RELEASES_URL. Trailing slashes are trimmed. To set the
+// host and API version separately, use WithHost and WithAPIVersion.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := normalizeBaseURL(baseURL)
+		if err != nil {
+			return err
+		}
+		c.URL = u
+		return nil
+	}
+}
+
+// WithHost sets the host of the releases API, optionally followed by the
+// path it is served under, e.g. "https://internal.example.com/hashicorp".
+// The API version set with WithAPIVersion, "v1" by default, is appended
+// to it to form the base URL. WithHost and WithAPIVersion take
+// precedence over WithBaseURL and RELEASES_URL.
+func WithHost(host string) ClientOption {
+	return func(c *Client) error {
+		u, err := normalizeBaseURL(host)
+		if err != nil {
+			return err
+		}
+		c.host = u
+		return nil
+	}
+}
+
+// WithAPIVersion sets the version path segment of the releases API, e.g.
+// "v1", which is appended to the host set with WithHost, or to the
+// default host, to form the base URL
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) error {
+		version = strings.Trim(version, "/")
+		if version == "" {
+			return fmt.Errorf("API version must not be empty")
+		}
+		c.apiVersion = version
+		return nil
+	}
+}
+
+// WithRequireAPIVersion requires the releases API to be the given
+// version, e.g. "v1", guarding long-running processes against being
+// pointed at a different version of the API. NewClient only checks that
+// the base URL selects that version. As the API does not report its
+// version, the live API is checked by a probe request: use
+// NewVerifiedClient to probe it on construction, or APIInfo later.
+func WithRequireAPIVersion(version string) ClientOption {
+	return func(c *Client) error {
+		if !apiVersionRegexp.MatchString(version) {
+			return fmt.Errorf("invalid API version %q, expected e.g. \"v1\"", version)
+		}
+		c.requireAPIVersion = version
+		return nil
+	}
+}
+
+// WithIfModifiedSince makes a call conditional: it sends an
+// If-Modified-Since header and returns ErrNotModified if the API reports
+// that the resource has not changed since t. When polling release
+// metadata for status changes such as a withdrawal, pass the latest of
+// the release's TimestampUpdated and Status.TimestampUpdated from the
+// previous fetch, since the former does not track status changes.
+func WithIfModifiedSince(t time.Time) CallOption {
+	return func(call *callConfig) {
+		call.ifModifiedSince = t
+	}
+}
+
+// WithAcceptHeader sets the Accept header sent with API requests, e.g.
+// for a mirror that negotiates content differently. It defaults to
+// "application/json; charset=utf-8".
+func WithAcceptHeader(accept string) ClientOption {
+	return func(c *Client) error {
+		if accept == "" {
+			return fmt.Errorf("accept header must not be empty")
+		}
+		c.accept = accept
+		return nil
+	}
+}
+
+// WithGETContentType sets whether the JSON Content-Type header is sent
+// on bodyless GET requests, where it is meaningless and rejected by some
+// strict proxies. It defaults to false; Content-Type is otherwise only
+// sent on requests with a body.
+func WithGETContentType(enabled bool) ClientOption {
+	return func(c *Client) error {
+		c.getContentType = enabled
+		return nil
+	}
+}
@@ -0,0 +1,187 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrScanLimitReached is returned by GetReleasesFiltered, along with the
+// releases kept so far, when it has scanned maxScanned releases without
+// reaching the end of the product's history or maxResults matches
+var ErrScanLimitReached = errors.New("scan limit reached")
+
+// errStopPaging may be returned by a pageReleases callback to stop
+// paging without an error
+var errStopPaging = errors.New("stop paging")
+
+// NextAfter returns the value to pass as ReleaseOptions.After to fetch
+// the page following this one, which is the creation timestamp of the
+// oldest release in the page. It returns an empty string for an empty
+// page.
+func (r ReleasesResponse) NextAfter() string {
+	if len(r) == 0 {
+		return ""
+	}
+	return r[len(r)-1].TimestampCreated
+}
+
+// ReleasesPage is a single page of releases together with what is needed
+// to request the next one
+type ReleasesPage struct {
+	// The releases in the page, newest first
+	Releases ReleasesResponse
+	// True if the API returned any releases, so a further page may
+	// exist. It is false once the end of the history, or of the window
+	// selected by ReleaseOptions.Before, has been reached; detecting the
+	// end of the history takes one final request returning no releases.
+	HasMore bool
+	// The value to pass as ReleaseOptions.After to request the next
+	// page. It is the creation timestamp of the oldest release the API
+	// returned, which may have been filtered out of Releases.
+	NextAfter string
+}
+
+// GetReleasesPage retrieves a single page of releases, like GetReleases,
+// and reports whether a further page may exist. Unlike GetReleases, it
+// always issues exactly one request: if ExcludePrereleases is set,
+// prereleases are dropped from the page, which may then hold fewer than
+// Limit releases, or none, while HasMore is still true. Loop on
+// HasMore, passing NextAfter as the next After, to page manually.
+func (c *Client) GetReleasesPage(ctx context.Context, product string, options *ReleaseOptions, opts ...CallOption) (*ReleasesPage, error) {
+	if product == "" {
+		return nil, fmt.Errorf("product must not be empty")
+	}
+	options = c.contextOptions(ctx, options)
+	page, err := c.getReleasesPage(ctx, product, options, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if options != nil && options.ExcludePrereleases {
+		stable := ReleasesResponse{}
+		for _, r := range page.Releases {
+			if !isPrerelease(r) {
+				stable = append(stable, r)
+			}
+		}
+		page.Releases = stable
+	}
+	return page, nil
+}
+
+// pageReleases pages through the releases of a product, starting from
+// the provided options, and calls fn with each page until the last page
+// has been read or fn returns an error. The provided options are not
+// modified.
+func (c *Client) pageReleases(ctx context.Context, product string, options *ReleaseOptions, fn func(ReleasesResponse) error) error {
+
+	// Copy the options, including any context defaults, so the
+	// After cursor can be advanced
+	opts := ReleaseOptions{}
+	if merged := c.contextOptions(ctx, options); merged != nil {
+		opts = *merged
+	}
+	if opts.Limit == 0 {
+		opts.Limit = c.defaultLimit
+	}
+
+	for {
+		page, err := c.GetReleasesPage(ctx, product, &opts)
+		if err != nil {
+			return err
+		}
+		if err := fn(page.Releases); err != nil {
+			if errors.Is(err, errStopPaging) {
+				return nil
+			}
+			return err
+		}
+		if !page.HasMore {
+			return nil
+		}
+		opts.After = page.NextAfter
+	}
+}
+
+// StreamReleases pages through the releases of a product in the
+// background and sends each release on the returned release channel,
+// newest first. The release channel is closed once all releases have
+// been sent or an error occurs, in which case the error is sent on the
+// error channel before it is closed. Paging stops when ctx is cancelled.
+func (c *Client) StreamReleases(ctx context.Context, product string, opts *ReleaseOptions) (<-chan Release, <-chan error) {
+	releases := make(chan Release)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(releases)
+		err := c.pageReleases(ctx, product, opts, func(page ReleasesResponse) error {
+			for _, r := range page {
+				select {
+				case releases <- r:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return releases, errs
+}
+
+// GetReleasesFiltered pages through the releases of a product, newest
+// first, and returns those for which keep returns true; a nil keep
+// keeps every release. Paging stops once maxResults releases have been
+// kept, or at the end of the product's history if maxResults is 0 or
+// less. To bound the requests made for a selective predicate, paging
+// also stops once maxScanned releases have been scanned, returning
+// ErrScanLimitReached along with the releases kept so far; a maxScanned
+// of 0 or less scans the whole history. If a page fails, the releases
+// kept so far are returned along with the error; callers that need the
+// complete result should discard them.
+func (c *Client) GetReleasesFiltered(ctx context.Context, product string, opts *ReleaseOptions, maxResults, maxScanned int, keep func(Release) bool) (ReleasesResponse, error) {
+	res := ReleasesResponse{}
+	scanned := 0
+	err := c.pageReleases(ctx, product, opts, func(page ReleasesResponse) error {
+		for _, r := range page {
+			if maxScanned > 0 && scanned >= maxScanned {
+				return fmt.Errorf("%w after %d releases of %s", ErrScanLimitReached, scanned, product)
+			}
+			scanned++
+			if keep != nil && !keep(r) {
+				continue
+			}
+			res = append(res, r)
+			if maxResults > 0 && len(res) >= maxResults {
+				return errStopPaging
+			}
+		}
+		return nil
+	})
+	return res, err
+}
+
+// BuildURLsForPlatform pages through the releases of a product, starting
+// from opts, and returns the download URL of the build for the given
+// operating system and architecture of each release, keyed by version.
+// Releases without a build for the platform are omitted. If both a
+// supported and an unsupported build exist, the supported one is used.
+// If a page fails, the URLs collected so far are returned along with
+// the error.
+func (c *Client) BuildURLsForPlatform(ctx context.Context, product string, os OS, arch Arch, opts *ReleaseOptions) (map[string]string, error) {
+	res := map[string]string{}
+	err := c.pageReleases(ctx, product, opts, func(page ReleasesResponse) error {
+		for _, r := range page {
+			if b, ok := r.Build(os, arch); ok {
+				c.warnBuild(r, *b)
+				res[r.Version] = b.URL
+			}
+		}
+		return nil
+	})
+	return res, err
+}
@@ -0,0 +1,78 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// pagedClient returns a client for a server holding n releases of vault
+// that returns at most pageSize per page, regardless of the requested
+// limit, and a pointer to the number of requests it has served
+func pagedClient(t *testing.T, n, pageSize int) (*Client, *int) {
+	t.Helper()
+	start := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	var releases ReleasesResponse
+	for i := 0; i < n; i++ {
+		releases = append(releases, Release{
+			Name:             "vault",
+			Version:          fmt.Sprintf("1.%d.0", n-i),
+			TimestampCreated: start.Add(-time.Duration(i) * time.Hour).Format(time.RFC3339),
+		})
+	}
+	requests := 0
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		after, err := time.Parse(time.RFC3339, r.URL.Query().Get("after"))
+		if err != nil {
+			t.Errorf("invalid after cursor: %v", err)
+		}
+		page := ReleasesResponse{}
+		for _, rel := range releases {
+			if created, _ := time.Parse(time.RFC3339, rel.TimestampCreated); created.Before(after) && len(page) < pageSize {
+				page = append(page, rel)
+			}
+		}
+		json.NewEncoder(w).Encode(page)
+	}), WithClock(func() time.Time { return start.Add(time.Hour) }))
+	return c, &requests
+}
+
+func TestPagingWithClampedPageSize(t *testing.T) {
+	c, requests := pagedClient(t, 12, 5)
+	res, err := c.GetReleasesFiltered(context.Background(), "vault", &ReleaseOptions{Limit: 20}, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("GetReleasesFiltered: %v", err)
+	}
+	if len(res) != 12 {
+		t.Errorf("got %d releases, want 12", len(res))
+	}
+	if *requests != 4 {
+		t.Errorf("got %d requests, want 4: three pages and a final empty one", *requests)
+	}
+}
+
+func TestGetReleasesFilteredScanLimit(t *testing.T) {
+	c, requests := pagedClient(t, 100, 5)
+	none := func(Release) bool { return false }
+	res, err := c.GetReleasesFiltered(context.Background(), "vault", &ReleaseOptions{Limit: 5}, 1, 12, none)
+	if !errors.Is(err, ErrScanLimitReached) {
+		t.Fatalf("got %v, want ErrScanLimitReached", err)
+	}
+	if len(res) != 0 {
+		t.Errorf("got %d releases, want none", len(res))
+	}
+	if *requests != 3 {
+		t.Errorf("got %d requests, want 3 to scan 12 releases", *requests)
+	}
+
+	// Reaching the end of the history within the limit is not an error
+	c, _ = pagedClient(t, 12, 5)
+	if _, err := c.GetReleasesFiltered(context.Background(), "vault", nil, 0, 12, none); err != nil {
+		t.Errorf("got %v scanning the whole history, want no error", err)
+	}
+}
@@ -0,0 +1,83 @@
+package hashicorpreleases
+
+import "sort"
+
+// OS is an operating system HashiCorp builds for, as it appears in
+// Build.OperatingSystem. Untyped string constants such as "linux" may be
+// passed wherever an OS is accepted; other strings need converting.
+type OS string
+
+// Arch is an architecture HashiCorp builds for, as it appears in
+// Build.Architecture. Untyped string constants such as "amd64" may be
+// passed wherever an Arch is accepted; other strings need converting.
+type Arch string
+
+// Operating systems HashiCorp ships builds for
+const (
+	OSDarwin  OS = "darwin"
+	OSFreeBSD OS = "freebsd"
+	OSLinux   OS = "linux"
+	OSNetBSD  OS = "netbsd"
+	OSOpenBSD OS = "openbsd"
+	OSSolaris OS = "solaris"
+	OSWindows OS = "windows"
+)
+
+// Architectures HashiCorp ships builds for
+const (
+	Arch386     Arch = "386"
+	ArchAMD64   Arch = "amd64"
+	ArchARM     Arch = "arm"
+	ArchARM64   Arch = "arm64"
+	ArchPPC64LE Arch = "ppc64le"
+	ArchS390X   Arch = "s390x"
+)
+
+// Platform is an operating system and architecture a release is built
+// for
+type Platform struct {
+	OS   OS
+	Arch Arch
+	// True if the build for this platform is not supported by HashiCorp
+	Unsupported bool
+}
+
+// Platforms returns the platforms the release is built for, sorted by
+// operating system then architecture. A platform with both a supported
+// and an unsupported build is listed once, as supported, matching the
+// build chosen by Build.
+func (r Release) Platforms() []Platform {
+	index := map[Platform]int{}
+	res := []Platform{}
+	for _, b := range r.Builds {
+		key := Platform{OS: OS(b.OperatingSystem), Arch: Arch(b.Architecture)}
+		if i, ok := index[key]; ok {
+			res[i].Unsupported = res[i].Unsupported && b.Unsupported
+			continue
+		}
+		index[key] = len(res)
+		res = append(res, Platform{OS: key.OS, Arch: key.Arch, Unsupported: b.Unsupported})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].OS != res[j].OS {
+			return res[i].OS < res[j].OS
+		}
+		return res[i].Arch < res[j].Arch
+	})
+	return res
+}
+
+// PlatformMatrix splits the platforms of the release into those
+// supported by HashiCorp and those built for convenience but
+// unsupported, each sorted by operating system then architecture
+func (r Release) PlatformMatrix() (supported, unsupported []Platform) {
+	supported, unsupported = []Platform{}, []Platform{}
+	for _, p := range r.Platforms() {
+		if p.Unsupported {
+			unsupported = append(unsupported, p)
+		} else {
+			supported = append(supported, p)
+		}
+	}
+	return supported, unsupported
+}
@@ -1,27 +1,148 @@
 package hashicorpreleases
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
+// ErrUnknownProduct is returned by NormalizeProduct when a name does not
+// match any HashiCorp product
+var ErrUnknownProduct = errors.New("unknown product")
+
+// productSuffixes are license suffixes users commonly append to product
+// names, e.g. "vault-enterprise", which are not part of the product name
+var productSuffixes = []string{"-enterprise", "-ent"}
+
+// defaultProductsRefreshInterval is how long the cached product list
+// is served by Products before it is fetched again
+const defaultProductsRefreshInterval = time.Hour
+
+// productsCache holds the product list served by Products
+type productsCache struct {
+	// refreshMu serializes refreshes by Products so that concurrent
+	// callers finding the cache stale issue a single request
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	products  ProductResponse
+	fetchedAt time.Time
+	interval  time.Duration
+}
+
 // ProductResponse is a list of all HashiCorp products
 type ProductResponse []string
 
 // GetProducts retrieves a list of all of the HashiCorp products
-func (c *Client) GetProducts() (ProductResponse, error) {
+func (c *Client) GetProducts(ctx context.Context, opts ...CallOption) (ProductResponse, error) {
 
 	// Start by creating request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/products", c.URL), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.ProductsURL(), nil)
 	if err != nil {
 		return nil, err
 	}
-	setJSONHeader(req)
 
 	// Issue the request against the API
 	res := ProductResponse{}
-	if err = c.sendRequest(req, &res); err != nil {
+	if err = c.sendRequest(req, &res, opts...); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
+
+// Products returns the list of HashiCorp products from a cache that is
+// refreshed once it is older than the configured refresh interval
+// (one hour by default, see WithProductsRefreshInterval). It is safe
+// for concurrent use.
+func (c *Client) Products(ctx context.Context) (ProductResponse, error) {
+
+	// Serve from the cache if it is still fresh
+	if res, ok := c.cachedProducts(); ok {
+		return res, nil
+	}
+
+	// Otherwise refresh it, unless a concurrent caller just did
+	c.products.refreshMu.Lock()
+	defer c.products.refreshMu.Unlock()
+	if res, ok := c.cachedProducts(); ok {
+		return res, nil
+	}
+	if err := c.RefreshProducts(ctx); err != nil {
+		return nil, err
+	}
+	c.products.mu.RLock()
+	defer c.products.mu.RUnlock()
+	return append(ProductResponse(nil), c.products.products...), nil
+}
+
+// cachedProducts returns a copy of the cached product list if it is
+// still fresh
+func (c *Client) cachedProducts() (ProductResponse, bool) {
+	c.products.mu.RLock()
+	defer c.products.mu.RUnlock()
+	if c.products.products == nil || time.Since(c.products.fetchedAt) >= c.products.interval {
+		return nil, false
+	}
+	return append(ProductResponse(nil), c.products.products...), true
+}
+
+// RefreshProducts fetches the product list and replaces the cached
+// value served by Products
+func (c *Client) RefreshProducts(ctx context.Context) error {
+	res, err := c.GetProducts(ctx)
+	if err != nil {
+		return err
+	}
+	c.products.mu.Lock()
+	defer c.products.mu.Unlock()
+	c.products.products = res
+	c.products.fetchedAt = time.Now()
+	return nil
+}
+
+// ProductExists reports whether product is a HashiCorp product, matching
+// names case-insensitively against the cached product list. An error is
+// only returned if the product list could not be fetched.
+func (c *Client) ProductExists(ctx context.Context, product string) (bool, error) {
+	products, err := c.Products(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range products {
+		if strings.EqualFold(p, product) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NormalizeProduct resolves a user-supplied product name to its canonical
+// name in the cached product list. Names are matched case-insensitively
+// and surrounding whitespace is ignored; if there is no exact match, an
+// "-enterprise" or "-ent" suffix is dropped before matching again.
+// ErrUnknownProduct is returned if the name matches no product.
+func (c *Client) NormalizeProduct(ctx context.Context, name string) (string, error) {
+	products, err := c.Products(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Try the name as given, then without a license suffix
+	candidates := []string{strings.TrimSpace(name)}
+	for _, suffix := range productSuffixes {
+		if n := candidates[0]; len(n) > len(suffix) && strings.EqualFold(n[len(n)-len(suffix):], suffix) {
+			candidates = append(candidates, n[:len(n)-len(suffix)])
+		}
+	}
+	for _, candidate := range candidates {
+		for _, p := range products {
+			if strings.EqualFold(p, candidate) {
+				return p, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownProduct, name)
+}
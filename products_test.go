@@ -0,0 +1,39 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProductsRefreshIgnoresClock(t *testing.T) {
+	var requests int32
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`["consul","vault"]`))
+	}),
+		WithClock(func() time.Time { return time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC) }),
+		WithProductsRefreshInterval(200*time.Millisecond),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Products(ctx); err != nil {
+			t.Fatalf("Products: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("got %d requests within the refresh interval, want 1", n)
+	}
+
+	// The fixed as-of clock must not keep the cache fresh
+	time.Sleep(200 * time.Millisecond)
+	if _, err := c.Products(ctx); err != nil {
+		t.Fatalf("Products: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Fatalf("got %d requests after the refresh interval, want 2", n)
+	}
+}
@@ -0,0 +1,70 @@
+package hashicorpreleases
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// recording tees a response body into a temporary file, which replaces
+// the recorded file once the body has been read in full
+type recording struct {
+	f      *os.File
+	target string
+	failed bool
+}
+
+// record starts recording the body of a response to u into the
+// directory set with WithResponseRecorder, at the path the releasestest
+// Transport serves it from, e.g. "v1/releases/vault/1.15.0.json". It
+// returns nil if no recorder is configured or the file cannot be
+// created, as recording must never fail a request.
+func (c *Client) record(u *url.URL) *recording {
+	if c.recordDir == "" {
+		return nil
+	}
+	name := strings.TrimPrefix(path.Clean("/"+u.Path), "/") + ".json"
+	target := filepath.Join(c.recordDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		c.warnf("error recording response from %s: %s", u.Path, err)
+		return nil
+	}
+	f, err := os.CreateTemp(filepath.Dir(target), ".recording-*")
+	if err != nil {
+		c.warnf("error recording response from %s: %s", u.Path, err)
+		return nil
+	}
+	return &recording{f: f, target: target}
+}
+
+// Write implements io.Writer. Write errors are remembered rather than
+// returned so that they do not interrupt decoding.
+func (r *recording) Write(p []byte) (int, error) {
+	if !r.failed {
+		if _, err := r.f.Write(p); err != nil {
+			r.failed = true
+		}
+	}
+	return len(p), nil
+}
+
+// finishRecording reads what is left of body, which tees into the
+// recording, and moves the recording into place. Renaming makes
+// concurrent recordings of the same path safe: the last one to finish
+// wins.
+func (c *Client) finishRecording(r *recording, body io.Reader) {
+	_, err := io.Copy(io.Discard, body)
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && !r.failed {
+		err = os.Rename(r.f.Name(), r.target)
+	}
+	if err != nil || r.failed {
+		os.Remove(r.f.Name())
+		c.warnf("error recording response to %s", r.target)
+	}
+}
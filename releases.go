@@ -1,27 +1,81 @@
 package hashicorpreleases
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// LatestVersion may be passed as the version to GetReleaseMetadata to
+// retrieve the metadata of the most recently created release of a product.
+const LatestVersion = "latest"
+
+const (
+	// defaultLimit is the number of results returned when no limit is set
+	defaultLimit = 10
+	// maxLimit is the maximum number of results the API returns per page
+	maxLimit = 20
+)
+
+// LicenseClass indicates whether a release is of an enterprise or an
+// open source product
+type LicenseClass string
+
+// License classes of HashiCorp releases
+const (
+	LicenseEnterprise LicenseClass = "enterprise"
+	LicenseOSS        LicenseClass = "oss"
+)
+
+// licenseClasses are the license classes releases can be filtered by
+var licenseClasses = []LicenseClass{LicenseEnterprise, LicenseOSS}
+
+// ErrNoReleases is returned when a product has no releases to resolve from
+var ErrNoReleases = errors.New("no releases found")
+
 type ReleaseOptions struct {
-	// Limit is the number of results returned. Maximum 20.
+	// Limit is the number of results returned. Maximum 20; must not
+	// be negative.
 	Limit int
 	// After is a timestamp used as a pagination marker,
 	// indicating that only releases that occurred prior to
 	// it should be retrieved. When fetching subsequent pages,
 	// this parameter should be set to the creation
-	// timestamp of the oldest release listed on the current page.
-	// This needs to be a RFC3339 timestamp in string form.
+	// timestamp of the oldest release listed on the current page,
+	// as returned by ReleasesResponse.NextAfter.
+	// This needs to be a RFC3339 timestamp in string form. It is
+	// converted to UTC before being sent, so the boundary is always
+	// evaluated in UTC whatever offset it is given with.
 	After string
-	// LicenseClass can be either "enterprise" or "oss", used for returning
-	// either enterprise versions or open source versions of HashiCorp
-	// products.
-	LicenseClass string
+	// LicenseClass can be either LicenseEnterprise or LicenseOSS, used for
+	// returning either enterprise versions or open source versions of
+	// HashiCorp products.
+	LicenseClass LicenseClass
+	// Before is a lower bound on the creation time of the releases
+	// returned. The API only supports an upper bound (After), so Before
+	// is enforced client-side: releases created before it are dropped,
+	// and the paginating helpers stop once they reach such a release.
+	// Together with After this selects the window [Before, After),
+	// inclusive of Before and exclusive of After.
+	Before time.Time
+	// ExcludePrereleases strips prereleases from the results client-side.
+	// GetReleases then keeps requesting pages until Limit stable releases
+	// have been collected or the end of the history is reached, so a call
+	// may issue several requests. If one fails, the stable releases
+	// collected so far are returned along with the error.
+	ExcludePrereleases bool
+	// NoAfter omits the after parameter when After is unset, so the API
+	// returns its own newest page. By default the current time of the
+	// client's clock is sent, which misses releases timestamped ahead of
+	// it, e.g. when the clocks of client and server disagree. It has no
+	// effect when After is set.
+	NoAfter bool
 }
 
 // ReleasesResponse is a list of Release
@@ -30,7 +84,11 @@ type ReleasesResponse []Release
 //ReleaseMetadataResponse is a Release
 type ReleaseMetadataResponse Release
 
-// Release represents a single release and its metadata
+// Release represents a single release and its metadata. The releases API
+// does not expose build provenance such as the Git commit or ref a
+// release was built from, so releases cannot be looked up by commit; use
+// the SourceRepositoryURL, which is empty for enterprise releases, and
+// the version tag instead.
 type Release struct {
 	// Builds is a list of builds
 	Builds []Build `json:"builds"`
@@ -39,7 +97,7 @@ type Release struct {
 	// True if and only if this product release is a prerelease.
 	IsPrerelease bool `json:"is_prerelease"`
 	// The license class indicates if this is an enterprise product or an open source product.
-	LicenseClass string `json:"license_class"`
+	LicenseClass LicenseClass `json:"license_class"`
 	// The product name
 	Name string `json:"name"`
 	// Status of the product release
@@ -74,7 +132,7 @@ type Release struct {
 	// Signature files may or may not embed the signing key ID in the filename.
 	ShaSumsSignaturesURL []string `json:"url_shasums_signatures"`
 	// URL for the product's source code repository. This field is empty for enterprise products.
-	SourceRepositoryURL string `json:"url_sorce_repository"`
+	SourceRepositoryURL string `json:"url_source_repository"`
 	// The version of this release
 	Version string `json:"version"`
 }
@@ -93,69 +151,308 @@ type Build struct {
 	URL string `json:"url"`
 }
 
+// Status represents the support status of a release
 type Status struct {
 	// Provides information about the most recent change; required when state="withdrawn"
-	Message string
+	Message string `json:"message"`
 	// The state name of the release
-	State string
+	State string `json:"state"`
 	// The timestamp when the release status was last updated
-	TimestampUpdated time.Time
+	TimestampUpdated time.Time `json:"timestamp_updated"`
+
+	unknownFields error
 }
 
 // GetReleases retrieves the release metadata for multiple releases.
 // This endpoint uses pagination for products with many releases.
 // Results are ordered by release creation time from newest to oldest.
-func (c *Client) GetReleases(product string, options *ReleaseOptions) (ReleasesResponse, error) {
+func (c *Client) GetReleases(ctx context.Context, product string, options *ReleaseOptions, opts ...CallOption) (ReleasesResponse, error) {
+	if product == "" {
+		return nil, fmt.Errorf("product must not be empty")
+	}
+
+	// Fill unset options from the context
+	options = c.contextOptions(ctx, options)
+	if options == nil || !options.ExcludePrereleases {
+		page, err := c.getReleasesPage(ctx, product, options, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return page.Releases, nil
+	}
+
+	// Keep paging until Limit stable releases have been collected
+	next := *options
+	if next.Limit == 0 {
+		next.Limit = c.defaultLimit
+	}
+	res := ReleasesResponse{}
+	for {
+		page, err := c.getReleasesPage(ctx, product, &next, opts...)
+		if err != nil {
+			return res, err
+		}
+		for _, r := range page.Releases {
+			if isPrerelease(r) {
+				continue
+			}
+			res = append(res, r)
+			if len(res) == next.Limit {
+				return res, nil
+			}
+		}
+		if !page.HasMore {
+			return res, nil
+		}
+		next.After = page.NextAfter
+	}
+}
+
+// getReleasesPage requests a single page of releases, without filtering
+// prereleases
+func (c *Client) getReleasesPage(ctx context.Context, product string, options *ReleaseOptions, opts ...CallOption) (*ReleasesPage, error) {
 
 	// Create the URL with ReleaseOptions as query parameters
-	u := fmt.Sprintf("%s/releases/%s", c.URL, product)
-	fullURL, err := handleReleaseOptions(u, options)
+	fullURL, err := c.ReleasesURL(product, options)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create the request
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	setJSONHeader(req)
 
-	// Issue the request against the API
+	// The API caps the page size at maxLimit
+	limit := c.defaultLimit
+	if options != nil && options.Limit != 0 {
+		limit = options.Limit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	// Issue the request against the API, sizing the result for a full page
 	res := ReleasesResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
+	if limit > 0 {
+		res = make(ReleasesResponse, 0, limit)
+	}
+	if err := c.sendRequest(req, &res, opts...); err != nil {
+		return nil, withRequest(err, product, "")
+	}
+
+	// Any non-empty page may be followed by more. Comparing its length
+	// to the requested limit would end paging early if the API clamped
+	// the page size, so the end is instead detected by an empty page.
+	page := &ReleasesPage{
+		Releases:  res,
+		HasMore:   len(res) > 0,
+		NextAfter: res.NextAfter(),
+	}
+
+	// Drop releases created before the lower bound. Releases are ordered
+	// newest first, so everything from the first such release is dropped
+	// and no later page can hold releases within the bound.
+	if options != nil && !options.Before.IsZero() {
+		for i, r := range res {
+			if createdAt(r).Before(options.Before) {
+				page.Releases = res[:i]
+				page.HasMore = false
+				break
+			}
+		}
 	}
-	return res, nil
+	return page, nil
 }
 
-// GetReleaseMetadata returns all metadata for a single product release
-func (c *Client) GetReleaseMetadata(product string, version string) (*ReleaseMetadataResponse, error) {
+// GetReleaseMetadata returns all metadata for a single product release.
+// If version is LatestVersion, it is resolved client-side by first listing
+// the newest release of the product (by creation time, so it may be a
+// prerelease) and then fetching that release's metadata. ErrNoReleases is
+// returned if the product has no releases.
+func (c *Client) GetReleaseMetadata(ctx context.Context, product string, version string, opts ...CallOption) (*ReleaseMetadataResponse, error) {
+	if product == "" {
+		return nil, fmt.Errorf("product must not be empty")
+	}
+	if version == "" {
+		return nil, fmt.Errorf("version must not be empty")
+	}
+
+	// Resolve "latest" to a concrete version via the releases endpoint
+	if version == LatestVersion {
+		latest, err := c.latestRelease(ctx, product, opts...)
+		if err != nil {
+			return nil, err
+		}
+		version = latest.Version
+	}
 
 	// Create the request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/releases/%s/%s", c.URL, product, version), nil)
+	u, err := c.ReleaseMetadataURL(product, version)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	setJSONHeader(req)
 
 	// Issue the request against the API
 	res := ReleaseMetadataResponse{}
-	if err := c.sendRequest(req, &res); err != nil {
-		return nil, err
+	if err := c.sendRequest(req, &res, opts...); err != nil {
+		return nil, withRequest(err, product, version)
 	}
+	c.warnWithdrawn(Release(res))
 	return &res, nil
 }
 
-func handleReleaseOptions(u string, options *ReleaseOptions) (string, error) {
-	limit := 10
-	after := time.Now().UTC().Format(time.RFC3339)
+// VersionExists reports whether a release of product with the given
+// version exists. The API does not document HEAD requests, so this
+// fetches the release's metadata without decoding it, mapping a 404
+// response to false. Any other failure, such as a transport error, is
+// returned as an error rather than false.
+func (c *Client) VersionExists(ctx context.Context, product, version string, opts ...CallOption) (bool, error) {
+	u, err := c.ReleaseMetadataURL(product, version)
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return false, err
+	}
+	var raw json.RawMessage
+	err = c.sendRequest(req, &raw, opts...)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, withRequest(err, product, version)
+	}
+	return true, nil
+}
+
+// GetReleaseCount returns the number of releases of a product. The API
+// does not expose a total count, so this pages through the product's
+// entire release history at the maximum page size, issuing one request
+// per 20 releases plus a final one to detect the end of the history.
+// The count includes prereleases. It includes both enterprise and open
+// source releases unless a default license class is set with
+// WithDefaultLicenseClass or WithOptionsFromContext, in which case only
+// releases of that class are counted. If a page fails, the count of the
+// releases read so far is returned along with the error.
+func (c *Client) GetReleaseCount(ctx context.Context, product string) (int, error) {
+	count := 0
+	err := c.pageReleases(ctx, product, &ReleaseOptions{Limit: maxLimit}, func(page ReleasesResponse) error {
+		count += len(page)
+		return nil
+	})
+	return count, err
+}
+
+// GetReleasesBetween returns all releases of a product created within
+// [from, to), paging through the releases newest first until it reaches
+// one created before from. If a page fails, the releases read so far
+// are returned along with the error.
+func (c *Client) GetReleasesBetween(ctx context.Context, product string, from, to time.Time) (ReleasesResponse, error) {
+	res := ReleasesResponse{}
+	options := &ReleaseOptions{
+		Limit:  maxLimit,
+		After:  to.UTC().Format(time.RFC3339),
+		Before: from,
+	}
+	err := c.pageReleases(ctx, product, options, func(page ReleasesResponse) error {
+		res = append(res, page...)
+		return nil
+	})
+	return res, err
+}
+
+// GetReleasesInRange returns the releases of a product whose version lies
+// within [fromVersion, toVersion], newest first, paging from opts (which
+// may be nil). Prereleases within the range are included unless
+// opts.ExcludePrereleases is set; releases with unparseable versions are
+// skipped. Releases are ordered by creation time, not version, and
+// backported patches of older lines are interleaved with newer
+// releases, so paging only stops at the first page holding no release
+// at or above fromVersion. If a page fails, the releases kept so far
+// are returned along with the error.
+func (c *Client) GetReleasesInRange(ctx context.Context, product, fromVersion, toVersion string, opts *ReleaseOptions) (ReleasesResponse, error) {
+	from, err := parseVersion(fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseVersion(toVersion)
+	if err != nil {
+		return nil, err
+	}
+	if from.compare(to) > 0 {
+		return nil, fmt.Errorf("version range is empty: %s is greater than %s", fromVersion, toVersion)
+	}
+
+	// Page at the maximum page size unless told otherwise
+	options := ReleaseOptions{Limit: maxLimit}
+	if opts != nil {
+		options = *opts
+		if options.Limit == 0 {
+			options.Limit = maxLimit
+		}
+	}
+
+	res := ReleasesResponse{}
+	err = c.pageReleases(ctx, product, &options, func(page ReleasesResponse) error {
+		reachable := false
+		for _, r := range page {
+			v, err := parseVersion(r.Version)
+			if err != nil || v.compare(from) < 0 {
+				continue
+			}
+			reachable = true
+			if v.compare(to) <= 0 {
+				res = append(res, r)
+			}
+		}
+		if len(page) > 0 && !reachable {
+			return errStopPaging
+		}
+		return nil
+	})
+	return res, err
+}
+
+// escapeVersion escapes a version for use as a URL path segment.
+// url.PathEscape leaves "+" untouched, which is ambiguous for enterprise
+// versions such as "1.15.0+ent", so it is encoded explicitly as "%2B".
+func escapeVersion(version string) string {
+	return strings.ReplaceAll(url.PathEscape(version), "+", "%2B")
+}
+
+func (c *Client) handleReleaseOptions(u string, options *ReleaseOptions) (string, error) {
+	limit := c.defaultLimit
+	after := c.now().UTC().Format(time.RFC3339)
+	licenseClass := c.defaultLicenseClass
 	if options != nil {
+		if options.Limit < 0 {
+			return "", fmt.Errorf("limit must not be negative, got %d", options.Limit)
+		}
 		if options.Limit != 0 {
 			limit = options.Limit
 		}
 		if options.After != "" {
-			after = options.After
+			t, err := parseTimestamp("after", options.After)
+			if err != nil {
+				return "", err
+			}
+			after = t.UTC().Format(time.RFC3339Nano)
+		} else if options.NoAfter {
+			after = ""
+		}
+		if options.LicenseClass != "" {
+			if err := validateLicenseClass(options.LicenseClass); err != nil {
+				return "", err
+			}
+			licenseClass = options.LicenseClass
 		}
 	}
 
@@ -165,10 +462,62 @@ func handleReleaseOptions(u string, options *ReleaseOptions) (string, error) {
 	}
 	values := urlA.Query()
 	values.Add("limit", strconv.Itoa(limit))
-	values.Add("after", after)
-	if options.LicenseClass != "" {
-		values.Add("license_class", options.LicenseClass)
+	if after != "" {
+		values.Add("after", after)
+	}
+	if licenseClass != "" {
+		values.Add("license_class", string(licenseClass))
 	}
 	urlA.RawQuery = values.Encode()
 	return urlA.String(), nil
 }
+
+// contextOptions returns options with unset fields filled from the
+// defaults derived from ctx by the function set with
+// WithOptionsFromContext. The provided options are not modified.
+func (c *Client) contextOptions(ctx context.Context, options *ReleaseOptions) *ReleaseOptions {
+	if c.optionsFromContext == nil {
+		return options
+	}
+	defaults := c.optionsFromContext(ctx)
+	if defaults == nil {
+		return options
+	}
+	merged := *defaults
+	if options != nil {
+		if options.Limit != 0 {
+			merged.Limit = options.Limit
+		}
+		if options.After != "" {
+			merged.After = options.After
+		}
+		if options.LicenseClass != "" {
+			merged.LicenseClass = options.LicenseClass
+		}
+		if !options.Before.IsZero() {
+			merged.Before = options.Before
+		}
+		if options.ExcludePrereleases {
+			merged.ExcludePrereleases = true
+		}
+		if options.NoAfter {
+			merged.NoAfter = true
+		}
+	}
+	return &merged
+}
+
+// IsEnterprise reports whether the release is of an enterprise product
+func (r Release) IsEnterprise() bool {
+	return r.LicenseClass == LicenseEnterprise
+}
+
+// validateLicenseClass checks that a license class is one the API accepts
+func validateLicenseClass(licenseClass LicenseClass) error {
+	for _, lc := range licenseClasses {
+		if licenseClass == lc {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid license class %q, must be one of %q", licenseClass, licenseClasses)
+}
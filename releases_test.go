@@ -0,0 +1,105 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetReleasesRejectsNegativeLimit(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	}))
+	_, err := c.GetReleases(context.Background(), "vault", &ReleaseOptions{Limit: -1})
+	if err == nil || !strings.Contains(err.Error(), "-1") {
+		t.Fatalf("got %v, want an error naming the negative limit", err)
+	}
+}
+
+func TestReleaseJSONRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("releasestest/testdata/v1/releases/vault/1.15.0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want Release
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("Unmarshal fixture: %v", err)
+	}
+	if want.Status.TimestampUpdated.IsZero() {
+		t.Fatal("fixture status has no timestamp")
+	}
+
+	marshaled, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got Release
+	if err := json.Unmarshal(marshaled, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the release:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestEmptyArgumentsIssueNoRequest(t *testing.T) {
+	c, err := NewClient(WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			t.Errorf("unexpected request %s", req.URL)
+			return nil, errors.New("unexpected request")
+		}),
+	}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := c.GetReleases(ctx, "", nil); err == nil {
+		t.Error("GetReleases with an empty product: got no error")
+	}
+	if _, err := c.GetReleaseMetadata(ctx, "", "1.15.0"); err == nil {
+		t.Error("GetReleaseMetadata with an empty product: got no error")
+	}
+	if _, err := c.GetReleaseMetadata(ctx, "vault", ""); err == nil {
+		t.Error("GetReleaseMetadata with an empty version: got no error")
+	}
+}
+
+func TestInvalidLicenseClassError(t *testing.T) {
+	c, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	_, err = c.ReleasesURL("vault", &ReleaseOptions{LicenseClass: "opensource"})
+	if err == nil {
+		t.Fatal("got no error for an invalid license class")
+	}
+	for _, want := range []string{`"opensource"`, `"enterprise"`, `"oss"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	if _, err := NewClient(WithDefaultLicenseClass("opensource")); err == nil {
+		t.Error("WithDefaultLicenseClass: got no error for an invalid license class")
+	}
+}
+
+func TestGetReleaseCount(t *testing.T) {
+	c, requests := pagedClient(t, 45, maxLimit)
+	count, err := c.GetReleaseCount(context.Background(), "vault")
+	if err != nil {
+		t.Fatalf("GetReleaseCount: %v", err)
+	}
+	if count != 45 {
+		t.Errorf("got count %d, want 45", count)
+	}
+	if *requests != 4 {
+		t.Errorf("got %d requests, want 4: three pages and a final empty one", *requests)
+	}
+}
@@ -0,0 +1,40 @@
+// Package releasesformat renders hashicorpreleases responses for
+// command-line tools, as aligned tables for people or JSON for machines.
+package releasesformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	hashicorpreleases "github.com/rizkybiz/hashicorpreleases-go"
+)
+
+// FormatReleasesTable writes the releases to w as an aligned table with
+// one row per release, showing its version, creation time in UTC,
+// whether it is a prerelease and its license class
+func FormatReleasesTable(w io.Writer, r hashicorpreleases.ReleasesResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tCREATED\tPRERELEASE\tLICENSE CLASS")
+	for _, rel := range r {
+		created := rel.TimestampCreated
+		if t, err := rel.CreatedAt(); err == nil {
+			created = t.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\n", rel.Version, created, rel.IsPrerelease, rel.LicenseClass)
+	}
+	return tw.Flush()
+}
+
+// FormatReleasesJSON writes the releases to w as an indented JSON array,
+// using the field names of the releases API
+func FormatReleasesJSON(w io.Writer, r hashicorpreleases.ReleasesResponse) error {
+	if r == nil {
+		r = hashicorpreleases.ReleasesResponse{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,121 @@
+// Package releasestest provides utilities for testing code that uses
+// the hashicorpreleases client without network access.
+package releasestest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	hashicorpreleases "github.com/rizkybiz/hashicorpreleases-go"
+)
+
+// baseURL is the releases API URL used by clients from NewTestClient
+const baseURL = "https://api.releases.hashicorp.com/v1"
+
+//go:embed testdata
+var embedded embed.FS
+
+// Fixtures returns the recorded API responses shipped with this package
+// as a starting template: the product list, a page of Vault releases and
+// the metadata of Vault 1.15.0 and of its enterprise counterpart
+// 1.15.0+ent, laid out as expected by Transport.
+func Fixtures() fs.FS {
+	sub, err := fs.Sub(embedded, "testdata")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Transport is an http.RoundTripper that serves responses from fixture
+// files instead of the network. A request is served the file named after
+// its URL path with a ".json" extension, e.g.
+// "v1/releases/vault/1.15.0.json" for GET /v1/releases/vault/1.15.0.
+// Fixtures holding a list of releases are paged as the API does: only
+// releases created before the "after" query parameter are served, at
+// most "limit" of them. Other query parameters are ignored. Requests
+// without a matching fixture receive a 404 response.
+type Transport struct {
+	Fixtures fs.FS
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := strings.TrimPrefix(path.Clean(req.URL.Path), "/") + ".json"
+	body, err := fs.ReadFile(t.Fixtures, name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return response(req, http.StatusNotFound, []byte(fmt.Sprintf(`{"code":404,"message":"no fixture for %s"}`, req.URL.Path))), nil
+	case err != nil:
+		return nil, err
+	}
+	return response(req, http.StatusOK, page(body, req.URL.Query())), nil
+}
+
+// page applies the after and limit query parameters to a fixture holding
+// a list of releases, ordered newest first. Other fixtures are returned
+// unchanged.
+func page(body []byte, query url.Values) []byte {
+	var releases []map[string]interface{}
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return body
+	}
+	after, err := time.Parse(time.RFC3339, query.Get("after"))
+	hasAfter := err == nil
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = len(releases)
+	}
+
+	res := []map[string]interface{}{}
+	for _, r := range releases {
+		if len(res) == limit {
+			break
+		}
+		created, _ := r["timestamp_created"].(string)
+		if t, err := time.Parse(time.RFC3339, created); hasAfter && err == nil && !t.Before(after) {
+			continue
+		}
+		res = append(res, r)
+	}
+	paged, err := json.Marshal(res)
+	if err != nil {
+		return body
+	}
+	return paged
+}
+
+func response(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// NewTestClient returns a client whose requests are served from fixtures
+// by a Transport. Pass Fixtures() to use the recorded fixtures shipped
+// with this package.
+func NewTestClient(fixtures fs.FS) *hashicorpreleases.Client {
+	return hashicorpreleases.MustNewClient(
+		hashicorpreleases.WithBaseURL(baseURL),
+		hashicorpreleases.WithHTTPClient(&http.Client{Transport: &Transport{Fixtures: fixtures}}),
+	)
+}
@@ -0,0 +1,144 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a request should be retried. ShouldRetry
+// is called after each attempt, numbered from 1, with either the
+// response or the transport error of that attempt, and returns whether
+// to retry and how long to wait before doing so.
+type RetryPolicy interface {
+	ShouldRetry(attempt int, resp *http.Response, err error) (bool, time.Duration)
+}
+
+// NoRetry is a RetryPolicy that never retries. It is the default.
+type NoRetry struct{}
+
+// ShouldRetry implements RetryPolicy
+func (NoRetry) ShouldRetry(int, *http.Response, error) (bool, time.Duration) {
+	return false, 0
+}
+
+// ErrorClass classifies the outcome of an attempt for retrying
+type ErrorClass int
+
+const (
+	// ErrorClassNone is an attempt that succeeded, or failed in a way
+	// that is final, such as a 404 response. It is not retried.
+	ErrorClassNone ErrorClass = iota
+	// ErrorClassRetryable is a transient failure that is retried
+	ErrorClassRetryable
+	// ErrorClassRateLimited is a transient failure caused by rate
+	// limiting. It is retried, waiting for the duration of the
+	// response's Retry-After header, capped by the policy's maximum
+	// delay, if it is given in seconds.
+	ErrorClassRateLimited
+	// ErrorClassFatal is a failure that must not be retried
+	ErrorClassFatal
+)
+
+// DefaultErrorClassifier is the classifier used by ExponentialBackoff
+// when none is set. Transport errors other than context cancellation,
+// 429 and 5xx responses are retryable. It never returns
+// ErrorClassRateLimited, so Retry-After headers are only honored by
+// custom classifiers that do.
+func DefaultErrorClassifier(resp *http.Response, err error) ErrorClass {
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		return ErrorClassFatal
+	case err != nil:
+		return ErrorClassRetryable
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return ErrorClassRetryable
+	}
+	return ErrorClassNone
+}
+
+// ExponentialBackoff is a RetryPolicy that retries transport errors,
+// 429 and 5xx responses, doubling the delay after each attempt
+type ExponentialBackoff struct {
+	// The maximum number of attempts, including the first one
+	MaxAttempts int
+	// The delay before the first retry
+	Base time.Duration
+	// The maximum delay between attempts; no cap if zero
+	Max time.Duration
+	// Classify decides which attempts are retried, e.g. to recognize
+	// the status codes of a caching proxy. It is called with either the
+	// response or the transport error of each attempt and defaults to
+	// DefaultErrorClassifier.
+	Classify func(resp *http.Response, err error) ErrorClass
+}
+
+// ShouldRetry implements RetryPolicy
+func (b ExponentialBackoff) ShouldRetry(attempt int, resp *http.Response, err error) (bool, time.Duration) {
+	classify := b.Classify
+	if classify == nil {
+		classify = DefaultErrorClassifier
+	}
+	class := classify(resp, err)
+	if attempt >= b.MaxAttempts || (class != ErrorClassRetryable && class != ErrorClassRateLimited) {
+		return false, 0
+	}
+	if class == ErrorClassRateLimited && resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			delay := time.Duration(secs) * time.Second
+			if b.Max > 0 && delay > b.Max {
+				delay = b.Max
+			}
+			return true, delay
+		}
+	}
+	delay := b.Base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if b.Max > 0 && delay >= b.Max {
+			break
+		}
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	return true, delay
+}
+
+// do issues the request with hc, retrying it as directed by the retry
+// policy. A request with a body is only retried if its body can be
+// recreated with GetBody.
+func (c *Client) do(hc *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := hc.Do(req)
+		retry, delay := policy.ShouldRetry(attempt, res, err)
+		if !retry || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return res, err
+		}
+
+		// Discard the failed response before waiting to retry
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		}
+
+		// Rewind the body for the next attempt
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
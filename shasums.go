@@ -0,0 +1,158 @@
+package hashicorpreleases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// shaSumsAlgoRegexp matches the algorithm in a SHASUMS file name,
+// e.g. "vault_1.15.0_SHA256SUMS"
+var shaSumsAlgoRegexp = regexp.MustCompile(`_(SHA\d+)SUMS(\.|$)`)
+
+// ShaSums is a parsed SHASUMS file
+type ShaSums struct {
+	// The checksum algorithm, e.g. "sha256"
+	Algo string
+	// Hex encoded checksums keyed by file name
+	Sums map[string]string
+}
+
+// ParseShaSums parses a SHASUMS file, detecting the checksum algorithm
+// from its file name or URL (e.g. "vault_1.15.0_SHA256SUMS"). An error is
+// returned if the algorithm is missing or not supported.
+func ParseShaSums(name string, r io.Reader) (*ShaSums, error) {
+
+	// Detect the algorithm from the file name
+	m := shaSumsAlgoRegexp.FindStringSubmatch(path.Base(name))
+	if m == nil {
+		return nil, fmt.Errorf("unable to detect checksum algorithm from %q", name)
+	}
+	algo := strings.ToLower(m[1])
+	if _, err := newHash(algo); err != nil {
+		return nil, err
+	}
+
+	// Parse "<checksum>  <filename>" lines
+	sums := &ShaSums{Algo: algo, Sums: map[string]string{}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed SHASUMS line %q", line)
+		}
+		sums.Sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading SHASUMS: %w", err)
+	}
+	return sums, nil
+}
+
+// GetShaSums fetches and parses the SHASUMS file of a release
+func (c *Client) GetShaSums(ctx context.Context, r Release) (*ShaSums, error) {
+	body, err := c.getFile(ctx, r.ShaSumsURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseShaSums(r.ShaSumsURL, bytes.NewReader(body))
+}
+
+// VerifyBuild hashes the downloaded artifact of a build with the
+// algorithm of sums and compares it against the build's checksum
+func VerifyBuild(sums *ShaSums, b Build, artifact io.Reader) error {
+	return sums.verify(b.Filename(), artifact)
+}
+
+// verify hashes the contents of the named file and compares it against
+// its checksum
+func (s *ShaSums) verify(name string, r io.Reader) error {
+
+	// Find the expected checksum
+	expected, ok := s.Sums[name]
+	if !ok {
+		return fmt.Errorf("no checksum found for %q", name)
+	}
+
+	// Hash the contents and compare
+	h, err := newHash(s.Algo)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return fmt.Errorf("error hashing %q: %w", name, err)
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %q: expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+// newHash returns the hash implementation for a checksum algorithm
+func newHash(algo string) (hash.Hash, error) {
+	switch algo {
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm %q", algo)
+}
+
+// getFile fetches a small file such as a SHASUMS file or its signature
+func (c *Client) getFile(ctx context.Context, u string) ([]byte, error) {
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	// Issue the request and read the body
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	res, err := c.do(c.HTTPClient, req, c.retryPolicy)
+	if err != nil {
+		c.observeRequest(0, 0, err)
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		err := &APIError{StatusCode: res.StatusCode, URL: u, RequestID: responseRequestID(res)}
+		err.readErrorBody(res.Body)
+		c.observeRequest(res.StatusCode, 0, err)
+		return nil, err
+	}
+	decoded, err := decodedBody(res, res.Body)
+	if err != nil {
+		c.observeRequest(res.StatusCode, 0, err)
+		return nil, err
+	}
+	body, err := io.ReadAll(decoded)
+	c.observeRequest(res.StatusCode, int64(len(body)), err)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", u, err)
+	}
+	return body, nil
+}
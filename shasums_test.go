@@ -0,0 +1,42 @@
+package hashicorpreleases
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetShaSumsGzipEncoded(t *testing.T) {
+	shasums := "0123abcd  vault_1.15.0_linux_amd64.zip\n4567ef89  vault_1.15.0_darwin_arm64.zip\n"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(shasums))
+		gz.Close()
+	})
+	want := map[string]string{
+		"vault_1.15.0_linux_amd64.zip":  "0123abcd",
+		"vault_1.15.0_darwin_arm64.zip": "4567ef89",
+	}
+
+	// With Accept-Encoding set explicitly, the transport leaves the body
+	// compressed, as it would for a CDN compressing unasked
+	headers := map[string]http.Header{
+		"transport":   nil,
+		"accept-gzip": {"Accept-Encoding": []string{"gzip"}},
+	}
+	for name, h := range headers {
+		t.Run(name, func(t *testing.T) {
+			c, srv := newTestClient(t, handler, WithHeaders(h))
+			sums, err := c.GetShaSums(context.Background(), Release{ShaSumsURL: srv.URL + "/vault_1.15.0_SHA256SUMS"})
+			if err != nil {
+				t.Fatalf("GetShaSums: %v", err)
+			}
+			if !reflect.DeepEqual(sums.Sums, want) {
+				t.Errorf("got %v, want %v", sums.Sums, want)
+			}
+		})
+	}
+}
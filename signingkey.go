@@ -0,0 +1,37 @@
+package hashicorpreleases
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// defaultSigningKeyURL is where HashiCorp publishes the public key its
+// SHASUMS files are signed with
+const defaultSigningKeyURL = "https://www.hashicorp.com/.well-known/pgp-key.txt"
+
+// signingKeyCache holds the public key served by GetSigningKey
+type signingKeyCache struct {
+	mu  sync.Mutex
+	url string
+	key []byte
+}
+
+// GetSigningKey returns HashiCorp's public OpenPGP key, as published at
+// https://www.hashicorp.com/.well-known/pgp-key.txt or the URL set with
+// WithSigningKeyURL. The key is fetched on first use and cached for the
+// lifetime of the client; a failed fetch is not cached. It is safe for
+// concurrent use.
+func (c *Client) GetSigningKey(ctx context.Context) (io.Reader, error) {
+	c.signingKey.mu.Lock()
+	defer c.signingKey.mu.Unlock()
+	if c.signingKey.key == nil {
+		key, err := c.getFile(ctx, c.signingKey.url)
+		if err != nil {
+			return nil, err
+		}
+		c.signingKey.key = key
+	}
+	return bytes.NewReader(c.signingKey.key), nil
+}
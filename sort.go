@@ -0,0 +1,82 @@
+package hashicorpreleases
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortByVersion sorts the releases in place by semantic version.
+// Releases with equal versions are ordered by TimestampCreated, and
+// releases whose version cannot be parsed are placed last.
+func (r ReleasesResponse) SortByVersion(ascending bool) {
+	sort.SliceStable(r, func(i, j int) bool {
+		vi, errI := parseVersion(r[i].Version)
+		vj, errJ := parseVersion(r[j].Version)
+		if errI != nil || errJ != nil {
+			return errI == nil && errJ != nil
+		}
+		c := vi.compare(vj)
+		if c == 0 {
+			c = compareTime(createdAt(r[i]), createdAt(r[j]))
+		}
+		if ascending {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+// SortByCreated sorts the releases in place by TimestampCreated
+func (r ReleasesResponse) SortByCreated(ascending bool) {
+	sort.SliceStable(r, func(i, j int) bool {
+		c := compareTime(createdAt(r[i]), createdAt(r[j]))
+		if ascending {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+// StableSort sorts the releases in place into a deterministic order:
+// newest first by TimestampCreated, then releases created at the same
+// time by descending semantic version (falling back to comparing the
+// version strings if either cannot be parsed), then by ascending Name.
+// This is the order the API documents, with its ties broken.
+func (r ReleasesResponse) StableSort() {
+	sort.SliceStable(r, func(i, j int) bool {
+		if c := compareTime(createdAt(r[i]), createdAt(r[j])); c != 0 {
+			return c > 0
+		}
+		if c := compareVersionStrings(r[i].Version, r[j].Version); c != 0 {
+			return c > 0
+		}
+		return r[i].Name < r[j].Name
+	})
+}
+
+// compareVersionStrings compares versions semantically if both can be
+// parsed and as strings otherwise
+func compareVersionStrings(a, b string) int {
+	if c, err := CompareVersions(a, b); err == nil && c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// createdAt parses the creation timestamp of a release, returning the
+// zero time if it is not a valid RFC3339 timestamp
+func createdAt(r Release) time.Time {
+	t, _ := r.CreatedAt()
+	return t
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package hashicorpreleases
+
+import "time"
+
+// StateWithdrawn is the Status.State of a release that has been withdrawn
+const StateWithdrawn = "withdrawn"
+
+// States of releases that have not been withdrawn
+const (
+	StateSupported   = "supported"
+	StateUnsupported = "unsupported"
+)
+
+// Lifecycle classifies a release by its prerelease flag and status
+type Lifecycle string
+
+// Lifecycle stages returned by Release.Lifecycle
+const (
+	LifecycleWithdrawn   Lifecycle = "withdrawn"
+	LifecyclePrerelease  Lifecycle = "prerelease"
+	LifecycleUnsupported Lifecycle = "unsupported"
+	LifecycleSupported   Lifecycle = "supported"
+	// The status state is missing or not one this library knows
+	LifecycleUnknown Lifecycle = "unknown"
+)
+
+// WithdrawalInfo describes whether and why a release was withdrawn
+type WithdrawalInfo struct {
+	// True if the release has been withdrawn
+	Withdrawn bool
+	// The reason given for the withdrawal, often referencing a CVE
+	Message string
+	// When the release was withdrawn
+	At time.Time
+}
+
+// Withdrawal returns the withdrawal details of the release. For releases
+// that are not withdrawn, Withdrawn is false and the other fields are
+// zero-valued.
+func (r Release) Withdrawal() WithdrawalInfo {
+	if r.Status.State != StateWithdrawn {
+		return WithdrawalInfo{}
+	}
+	return WithdrawalInfo{
+		Withdrawn: true,
+		Message:   r.Status.Message,
+		At:        r.Status.TimestampUpdated,
+	}
+}
+
+// Lifecycle returns the single lifecycle stage of the release, combining
+// IsPrerelease, the version and Status.State, which can disagree. The
+// first matching rule wins: a withdrawn release, prerelease or not, is
+// LifecycleWithdrawn; a prerelease, by flag or by version such as
+// "1.15.0-rc1", is LifecyclePrerelease whatever its support state;
+// otherwise the support state decides between LifecycleUnsupported and
+// LifecycleSupported, or LifecycleUnknown if it is missing or unknown.
+func (r Release) Lifecycle() Lifecycle {
+	switch {
+	case r.Status.State == StateWithdrawn:
+		return LifecycleWithdrawn
+	case isPrerelease(r):
+		return LifecyclePrerelease
+	case r.Status.State == StateUnsupported:
+		return LifecycleUnsupported
+	case r.Status.State == StateSupported:
+		return LifecycleSupported
+	}
+	return LifecycleUnknown
+}
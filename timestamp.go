@@ -0,0 +1,105 @@
+package hashicorpreleases
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timestampLayouts are the layouts tried, in order, when parsing a
+// timestamp from the API, which sends them both with and without
+// fractional seconds
+var timestampLayouts = []string{time.RFC3339Nano, time.RFC3339}
+
+// parseTimestamp parses the named timestamp field of an API response
+func parseTimestamp(field, value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s timestamp %q: expected RFC3339", field, value)
+}
+
+// CreatedAt parses the TimestampCreated field of the release
+func (r Release) CreatedAt() (time.Time, error) {
+	return parseTimestamp("timestamp_created", r.TimestampCreated)
+}
+
+// UpdatedAt parses the TimestampUpdated field of the release
+func (r Release) UpdatedAt() (time.Time, error) {
+	return parseTimestamp("timestamp_updated", r.TimestampUpdated)
+}
+
+// UnmarshalJSON decodes a release status, parsing its timestamp with or
+// without fractional seconds. A missing or empty timestamp is left zero.
+// Unknown fields are ignored, but remembered so that a client with
+// strict decoding enabled can reject them.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Message          string `json:"message"`
+		State            string `json:"state"`
+		TimestampUpdated string `json:"timestamp_updated"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	unknownFields := dec.Decode(&raw)
+	if unknownFields != nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		unknownFields = fmt.Errorf("status: %w", unknownFields)
+	}
+	*s = Status{Message: raw.Message, State: raw.State, unknownFields: unknownFields}
+	if raw.TimestampUpdated != "" {
+		t, err := parseTimestamp("status.timestamp_updated", raw.TimestampUpdated)
+		if err != nil {
+			return err
+		}
+		s.TimestampUpdated = t
+	}
+	return nil
+}
+
+// statusType is the type of Status, which decodeJSON looks for when
+// decoding strictly
+var statusType = reflect.TypeOf(Status{})
+
+// statusUnknownFields returns the error recorded for the first Status
+// found in v that was decoded from an object with unknown fields, as
+// Status decodes itself and cannot tell whether decoding is strict
+func statusUnknownFields(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return statusUnknownFields(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := statusUnknownFields(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := statusUnknownFields(iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == statusType && v.CanInterface() {
+			return v.Interface().(Status).unknownFields
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				if err := statusUnknownFields(v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusTimestampLayouts(t *testing.T) {
+	for _, ts := range []string{"2023-09-25T18:20:19Z", "2023-09-25T18:20:19.123456Z"} {
+		c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"name":"vault","version":"1.15.0","status":{"state":"supported","timestamp_updated":%q}}`, ts)
+		}), WithStrictDecoding())
+		r, err := c.GetReleaseMetadata(context.Background(), "vault", "1.15.0")
+		if err != nil {
+			t.Fatalf("GetReleaseMetadata with timestamp %s: %v", ts, err)
+		}
+		if want, _ := time.Parse(time.RFC3339Nano, ts); !r.Status.TimestampUpdated.Equal(want) {
+			t.Errorf("got timestamp %s, want %s", r.Status.TimestampUpdated, want)
+		}
+	}
+}
+
+func TestStrictDecodingRejectsUnknownStatusFields(t *testing.T) {
+	body := `{"name":"vault","version":"1.15.0","status":{"state":"supported","new_field":1}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	c, _ := newTestClient(t, handler)
+	if _, err := c.GetReleaseMetadata(context.Background(), "vault", "1.15.0"); err != nil {
+		t.Fatalf("got %v, want unknown status fields accepted by default", err)
+	}
+
+	c, _ = newTestClient(t, handler, WithStrictDecoding())
+	_, err := c.GetReleaseMetadata(context.Background(), "vault", "1.15.0")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("got %v, want a DecodeError in strict mode", err)
+	}
+}
+
+func TestStatusTimestampErrors(t *testing.T) {
+	var s Status
+	if err := json.Unmarshal([]byte(`{"state":"supported","timestamp_updated":""}`), &s); err != nil {
+		t.Errorf("got %v for an empty timestamp, want it left zero", err)
+	} else if !s.TimestampUpdated.IsZero() {
+		t.Errorf("got timestamp %s for an empty timestamp, want zero", s.TimestampUpdated)
+	}
+
+	err := json.Unmarshal([]byte(`{"state":"supported","timestamp_updated":"yesterday"}`), &s)
+	if err == nil {
+		t.Fatal("got no error for an invalid timestamp")
+	}
+	for _, want := range []string{"status.timestamp_updated", `"yesterday"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestStrictDecodingRejectsUnknownStatusFieldsInPages(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"vault","version":"1.15.0","status":{"state":"supported"}},{"name":"vault","version":"1.14.0","status":{"state":"supported","new_field":1}}]`))
+	}), WithStrictDecoding())
+	_, err := c.GetReleases(context.Background(), "vault", nil)
+	if err == nil || !strings.Contains(err.Error(), "new_field") {
+		t.Fatalf("got %v, want an error naming the unknown status field", err)
+	}
+}
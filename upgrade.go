@@ -0,0 +1,78 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"fmt"
+)
+
+// UpgradePolicy selects the steps of an upgrade path from candidates,
+// the stable, non-withdrawn releases newer than the starting version up
+// to and including the target, sorted by ascending version. The steps
+// must be returned in ascending order and end with the target.
+type UpgradePolicy func(candidates ReleasesResponse) ReleasesResponse
+
+// UpgradeEachMinor is the default UpgradePolicy. It steps through the
+// latest patch release of each minor line, including the starting one
+// if a newer patch exists, and ends with the target, as products such as
+// Consul and Vault require for sequential minor upgrades.
+func UpgradeEachMinor(candidates ReleasesResponse) ReleasesResponse {
+	res := candidates.LatestPerMinor()
+	res.SortByVersion(true)
+	return res
+}
+
+// UpgradeDirect is an UpgradePolicy that goes straight to the target
+func UpgradeDirect(candidates ReleasesResponse) ReleasesResponse {
+	if len(candidates) == 0 {
+		return ReleasesResponse{}
+	}
+	return candidates[len(candidates)-1:]
+}
+
+// UpgradePath returns the releases to step through, in ascending order,
+// to upgrade a product from one version to another, following the
+// UpgradeEachMinor policy. See UpgradePathWithPolicy.
+func (c *Client) UpgradePath(ctx context.Context, product, from, to string) (ReleasesResponse, error) {
+	return c.UpgradePathWithPolicy(ctx, product, from, to, UpgradeEachMinor)
+}
+
+// UpgradePathWithPolicy returns the releases to step through, in
+// ascending order, to upgrade a product from one version to another,
+// as selected by policy. Only stable releases that have not been
+// withdrawn and share the target's build metadata, such as "+ent", are
+// considered. The path is empty if from equals to, and an error is
+// returned if the target release does not exist, has been withdrawn or
+// is older than from.
+func (c *Client) UpgradePathWithPolicy(ctx context.Context, product, from, to string, policy UpgradePolicy) (ReleasesResponse, error) {
+	fromVersion, err := parseVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := parseVersion(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the candidate releases within the range
+	releases, err := c.GetReleasesInRange(ctx, product, from, to, &ReleaseOptions{ExcludePrereleases: true})
+	if err != nil {
+		return nil, err
+	}
+	target, ok := releases.FindBySemver(to)
+	if !ok {
+		return nil, fmt.Errorf("target release %s %s not found", product, to)
+	}
+	if target.Withdrawal().Withdrawn {
+		return nil, fmt.Errorf("target release %s %s has been withdrawn", product, to)
+	}
+	candidates := ReleasesResponse{}
+	for _, r := range releases {
+		v, err := parseVersion(r.Version)
+		if err != nil || v.compare(fromVersion) <= 0 || v.metadata != toVersion.metadata || r.Withdrawal().Withdrawn {
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+	candidates.SortByVersion(true)
+	return policy(candidates), nil
+}
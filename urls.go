@@ -0,0 +1,40 @@
+package hashicorpreleases
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ProductsURL returns the URL GetProducts requests
+func (c *Client) ProductsURL() string {
+	return fmt.Sprintf("%s/products", c.URL)
+}
+
+// ReleasesURL returns the URL GetReleases requests for a single page of
+// releases, including its query parameters, without issuing a request.
+// Options are validated as GetReleases would; options attached to a
+// context with WithOptionsFromContext are not applied. When After is
+// unset, the current time is used, as it would be for a request made now.
+func (c *Client) ReleasesURL(product string, options *ReleaseOptions) (string, error) {
+	if product == "" {
+		return "", fmt.Errorf("product must not be empty")
+	}
+	u := fmt.Sprintf("%s/releases/%s", c.URL, url.PathEscape(product))
+	return c.handleReleaseOptions(u, options)
+}
+
+// ReleaseMetadataURL returns the URL GetReleaseMetadata requests for a
+// release, without issuing a request. LatestVersion cannot be resolved
+// without a request, so it is rejected.
+func (c *Client) ReleaseMetadataURL(product, version string) (string, error) {
+	if product == "" {
+		return "", fmt.Errorf("product must not be empty")
+	}
+	if version == "" {
+		return "", fmt.Errorf("version must not be empty")
+	}
+	if version == LatestVersion {
+		return "", fmt.Errorf("version %q must be resolved by a request", LatestVersion)
+	}
+	return fmt.Sprintf("%s/releases/%s/%s", c.URL, url.PathEscape(product), escapeVersion(version)), nil
+}
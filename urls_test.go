@@ -0,0 +1,37 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPathSegmentsAreEscaped(t *testing.T) {
+	var requestURIs []string
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURIs = append(requestURIs, r.RequestURI)
+		if strings.Contains(r.URL.Path, "1.15.0") {
+			w.Write([]byte(`{"name":"my prod","version":"1.15.0+ent"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	ctx := context.Background()
+
+	if _, err := c.GetReleaseMetadata(ctx, "my prod", "1.15.0+ent"); err != nil {
+		t.Fatalf("GetReleaseMetadata: %v", err)
+	}
+	if _, err := c.GetReleases(ctx, "my prod", nil); err != nil {
+		t.Fatalf("GetReleases: %v", err)
+	}
+	if len(requestURIs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requestURIs))
+	}
+	if want := "/v1/releases/my%20prod/1.15.0%2Bent"; requestURIs[0] != want {
+		t.Errorf("got request URI %s, want %s", requestURIs[0], want)
+	}
+	if want := "/v1/releases/my%20prod?"; !strings.HasPrefix(requestURIs[1], want) {
+		t.Errorf("got request URI %s, want prefix %s", requestURIs[1], want)
+	}
+}
@@ -0,0 +1,51 @@
+package hashicorpreleases
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the import path of this module
+const modulePath = "github.com/rizkybiz/hashicorpreleases-go"
+
+// libraryVersion is the version of this library, updated with each
+// release. Version reports it when the module version cannot be read
+// from the build info, e.g. when built from a local checkout.
+const libraryVersion = "v0.1.0"
+
+var (
+	versionOnce     sync.Once
+	resolvedVersion string
+)
+
+// Version returns the version of this library as recorded in the build
+// info of the running binary, or the version this copy of the library
+// was released as if it is unavailable
+func Version() string {
+	versionOnce.Do(func() {
+		resolvedVersion = libraryVersion
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		mods := append([]*debug.Module{&info.Main}, info.Deps...)
+		for _, m := range mods {
+			if m.Path != modulePath {
+				continue
+			}
+			if m.Replace != nil {
+				m = m.Replace
+			}
+			if m.Version != "" && m.Version != "(devel)" {
+				resolvedVersion = m.Version
+			}
+			return
+		}
+	})
+	return resolvedVersion
+}
+
+// userAgent returns the User-Agent sent with every request
+func userAgent() string {
+	return "hashicorpreleases-go/" + Version()
+}
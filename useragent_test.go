@@ -0,0 +1,27 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var ua string
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.Header.Get("User-Agent")
+		w.Write([]byte(`["vault"]`))
+	}))
+	if _, err := c.GetProducts(context.Background()); err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+
+	// Test binaries carry no module version, so the constant is reported
+	if want := "hashicorpreleases-go/" + libraryVersion; ua != want {
+		t.Errorf("got User-Agent %q, want %q", ua, want)
+	}
+	if !strings.HasPrefix(Version(), "v") {
+		t.Errorf("Version() = %q, want a semantic version", Version())
+	}
+}
@@ -0,0 +1,229 @@
+package hashicorpreleases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// VerifyShaSumsLocal verifies a detached OpenPGP signature of a SHASUMS
+// file entirely offline, using readers over local copies of the SHASUMS
+// file, its signature and the signer's public key. The signature and the
+// public key may be either binary or ASCII armored.
+func VerifyShaSumsLocal(shasums io.Reader, signature io.Reader, pubKey io.Reader) error {
+
+	// Read the public key into a keyring
+	keyring, err := readKeyRing(pubKey)
+	if err != nil {
+		return err
+	}
+	return checkSignature(keyring, shasums, signature)
+}
+
+// VerifyOption configures VerifyShaSums
+type VerifyOption func(*verifyConfig)
+
+type verifyConfig struct {
+	matchKeyID bool
+}
+
+// WithKeyIDMatching makes VerifyShaSums verify only the signature whose
+// file name embeds the ID of the supplied public key (or one of its
+// subkeys), e.g. "vault_1.15.0_SHA256SUMS.72D7468F.sig". If no signature
+// file name matches, every signature is tried as usual.
+func WithKeyIDMatching() VerifyOption {
+	return func(cfg *verifyConfig) {
+		cfg.matchKeyID = true
+	}
+}
+
+// VerifyShaSums fetches the SHASUMS file of a release and verifies it
+// against the release's signature files using pubKey, returning the
+// parsed checksums once a signature verifies. By default each signature
+// is tried in turn; see WithKeyIDMatching. If pubKey is nil, HashiCorp's
+// public key is fetched with GetSigningKey.
+func (c *Client) VerifyShaSums(ctx context.Context, r Release, pubKey io.Reader, opts ...VerifyOption) (*ShaSums, error) {
+	cfg := verifyConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if pubKey == nil {
+		key, err := c.GetSigningKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pubKey = key
+	}
+	keyring, err := readKeyRing(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.ShaSumsSignaturesURL) == 0 {
+		return nil, fmt.Errorf("release %s %s has no SHASUMS signatures", r.Name, r.Version)
+	}
+
+	// Narrow the signatures down to those made by the key, if possible
+	sigURLs := r.ShaSumsSignaturesURL
+	if cfg.matchKeyID {
+		if matched := matchSignatureKeyIDs(keyring, sigURLs); len(matched) > 0 {
+			sigURLs = matched
+		}
+	}
+
+	// Fetch the SHASUMS file and try each signature
+	body, err := c.getFile(ctx, r.ShaSumsURL)
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, u := range sigURLs {
+		sig, err := c.getFile(ctx, u)
+		if err == nil {
+			err = checkSignature(keyring, bytes.NewReader(body), bytes.NewReader(sig))
+		}
+		if err == nil {
+			return ParseShaSums(r.ShaSumsURL, bytes.NewReader(body))
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", path.Base(u), err))
+	}
+	return nil, fmt.Errorf("no valid SHASUMS signature found: %s", strings.Join(errs, "; "))
+}
+
+// ShaSumsSigners downloads the signature files of a release and returns
+// the IDs of the keys that made the signatures, as 16-digit uppercase
+// hex strings such as "34365D9472D7468F", without verifying them. IDs
+// are deduplicated and listed in the order encountered. An error names
+// the first signature file that could not be fetched or parsed.
+func (c *Client) ShaSumsSigners(ctx context.Context, r Release) ([]string, error) {
+	if len(r.ShaSumsSignaturesURL) == 0 {
+		return nil, fmt.Errorf("release %s %s has no SHASUMS signatures", r.Name, r.Version)
+	}
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, u := range r.ShaSumsSignaturesURL {
+		sig, err := c.getFile(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		sigIDs, err := signatureKeyIDs(sig)
+		if err != nil {
+			return nil, fmt.Errorf("error reading signature %s: %w", path.Base(u), err)
+		}
+		for _, id := range sigIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// signatureKeyIDs returns the issuer key IDs of the signature packets in
+// a binary or ASCII armored signature file
+func signatureKeyIDs(sig []byte) ([]string, error) {
+	var r io.Reader = bytes.NewReader(sig)
+	if isArmored(bufio.NewReader(bytes.NewReader(sig))) {
+		block, err := armor.Decode(r)
+		if err != nil {
+			return nil, err
+		}
+		r = block.Body
+	}
+
+	ids := []string{}
+	packets := packet.NewReader(r)
+	for {
+		p, err := packets.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := p.(*packet.Signature); ok && s.IssuerKeyId != nil {
+			ids = append(ids, fmt.Sprintf("%016X", *s.IssuerKeyId))
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no signature with an issuer key ID found")
+	}
+	return ids, nil
+}
+
+// matchSignatureKeyIDs returns the signature URLs whose file name embeds
+// the short or long ID of a key in the keyring
+func matchSignatureKeyIDs(keyring openpgp.EntityList, sigURLs []string) []string {
+	ids := []string{}
+	addID := func(id uint64) {
+		ids = append(ids, fmt.Sprintf("%016X", id), fmt.Sprintf("%08X", uint32(id)))
+	}
+	for _, e := range keyring {
+		addID(e.PrimaryKey.KeyId)
+		for _, sk := range e.Subkeys {
+			addID(sk.PublicKey.KeyId)
+		}
+	}
+
+	matched := []string{}
+	for _, u := range sigURLs {
+		name := strings.ToUpper(path.Base(u))
+		for _, id := range ids {
+			if strings.Contains(name, "."+id+".") {
+				matched = append(matched, u)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// checkSignature verifies a binary or ASCII armored detached signature
+func checkSignature(keyring openpgp.EntityList, signed io.Reader, signature io.Reader) error {
+	var err error
+	sig := bufio.NewReader(signature)
+	if isArmored(sig) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyring, signed, sig, nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyring, signed, sig, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("error verifying SHASUMS signature: %w", err)
+	}
+	return nil
+}
+
+// readKeyRing reads an ASCII armored or binary OpenPGP public key
+func readKeyRing(pubKey io.Reader) (openpgp.EntityList, error) {
+	key, err := io.ReadAll(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	}
+	var keyring openpgp.EntityList
+	if isArmored(bufio.NewReader(bytes.NewReader(key))) {
+		keyring, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+	} else {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(key))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	}
+	return keyring, nil
+}
+
+// isArmored reports whether the buffered data begins with an ASCII armor
+// header, without consuming it
+func isArmored(r *bufio.Reader) bool {
+	header := []byte("-----BEGIN PGP")
+	b, _ := r.Peek(len(header))
+	return bytes.Equal(b, header)
+}
@@ -0,0 +1,101 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	// ErrFileMissing is reported by VerifyDirectory for a file listed in
+	// the SHASUMS file that is not present in the directory
+	ErrFileMissing = errors.New("file listed in SHASUMS is missing")
+	// ErrFileNotInShaSums is reported by VerifyDirectory for a file in
+	// the directory that is not listed in the SHASUMS file
+	ErrFileNotInShaSums = errors.New("file is not listed in SHASUMS")
+)
+
+// VerifyDirectory fetches the SHASUMS file of a release and verifies the
+// checksums of the files in dir against it, hashing them concurrently.
+// The returned map holds a result for every file listed in the SHASUMS
+// file and every regular file in dir: nil if its checksum matches,
+// ErrFileMissing if it is listed but absent, ErrFileNotInShaSums if it
+// is present but not listed, or the verification error otherwise. The
+// SHASUMS file and its signatures are ignored if present in dir.
+func (c *Client) VerifyDirectory(ctx context.Context, r Release, dir string) (map[string]error, error) {
+	sums, err := c.GetShaSums(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files that accompany the artifacts but are not listed
+	ignored := map[string]bool{path.Base(r.ShaSumsURL): true}
+	for _, u := range r.ShaSumsSignaturesURL {
+		ignored[path.Base(u)] = true
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := map[string]error{}
+	present := map[string]bool{}
+	var unlisted []string
+	sem := make(chan struct{}, defaultConcurrency)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || ignored[name] {
+			continue
+		}
+		present[name] = true
+		if _, ok := sums.Sums[name]; !ok {
+			unlisted = append(unlisted, name)
+			continue
+		}
+
+		// Hash the listed files using a bounded pool
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := verifyFile(sums, name, filepath.Join(dir, name))
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = err
+		}(name)
+	}
+	wg.Wait()
+
+	// The workers are done, so the results can be completed unguarded
+	for _, name := range unlisted {
+		results[name] = ErrFileNotInShaSums
+	}
+	for name := range sums.Sums {
+		if !present[name] {
+			results[name] = ErrFileMissing
+		}
+	}
+	return results, nil
+}
+
+// verifyFile verifies the checksum of the file at p
+func verifyFile(sums *ShaSums, name, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return sums.verify(name, f)
+}
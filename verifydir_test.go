@@ -0,0 +1,74 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_listed.zip":   "a",
+		"b_unlisted.txt": "b",
+		"c_listed.zip":   "c",
+		"d_mismatch.zip": "d",
+		"e_unlisted.txt": "e",
+		"f_listed.zip":   "f",
+		"g_unlisted.txt": "g",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// List some of the files, one with a wrong checksum and one absent
+	var shasums string
+	for _, name := range []string{"a_listed.zip", "c_listed.zip", "f_listed.zip"} {
+		sum := sha256.Sum256([]byte(files[name]))
+		shasums += fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	wrong := sha256.Sum256([]byte("not d"))
+	shasums += fmt.Sprintf("%s  d_mismatch.zip\n", hex.EncodeToString(wrong[:]))
+	missing := sha256.Sum256([]byte("h"))
+	shasums += fmt.Sprintf("%s  h_missing.zip\n", hex.EncodeToString(missing[:]))
+
+	c, srv := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, shasums)
+	}))
+	r := Release{ShaSumsURL: srv.URL + "/vault_1.15.0_SHA256SUMS"}
+
+	results, err := c.VerifyDirectory(context.Background(), r, dir)
+	if err != nil {
+		t.Fatalf("VerifyDirectory: %v", err)
+	}
+	for name, want := range map[string]error{
+		"a_listed.zip":   nil,
+		"b_unlisted.txt": ErrFileNotInShaSums,
+		"c_listed.zip":   nil,
+		"e_unlisted.txt": ErrFileNotInShaSums,
+		"f_listed.zip":   nil,
+		"g_unlisted.txt": ErrFileNotInShaSums,
+		"h_missing.zip":  ErrFileMissing,
+	} {
+		got, ok := results[name]
+		if !ok {
+			t.Errorf("no result for %s", name)
+		} else if !errors.Is(got, want) {
+			t.Errorf("result for %s = %v, want %v", name, got, want)
+		}
+	}
+	if results["d_mismatch.zip"] == nil {
+		t.Errorf("result for d_mismatch.zip = nil, want a checksum error")
+	}
+	if len(results) != 8 {
+		t.Errorf("got %d results, want 8: %v", len(results), results)
+	}
+}
@@ -0,0 +1,149 @@
+package hashicorpreleases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version such as "1.15.0-rc1+ent"
+type semver struct {
+	major      int
+	minor      int
+	patch      int
+	prerelease string
+	metadata   string
+}
+
+// parseVersion parses a HashiCorp release version. A leading "v" is
+// allowed and missing minor or patch numbers are treated as zero.
+func parseVersion(v string) (semver, error) {
+	var sv semver
+	s := strings.TrimPrefix(v, "v")
+
+	// Split off build metadata, then the prerelease
+	if i := strings.Index(s, "+"); i >= 0 {
+		sv.metadata = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		sv.prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	// Parse the numeric segments
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver{}, fmt.Errorf("invalid version %q", v)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version %q", v)
+		}
+		nums[i] = n
+	}
+	sv.major, sv.minor, sv.patch = nums[0], nums[1], nums[2]
+	return sv, nil
+}
+
+// compare returns -1, 0 or 1 if v is less than, equal to or greater
+// than o. Build metadata does not affect precedence.
+func (v semver) compare(o semver) int {
+	if c := compareInt(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.patch, o.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.prerelease, o.prerelease)
+}
+
+// minorLine returns the "major.minor" version line of v
+func (v semver) minorLine() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// CompareVersions compares two release versions using semantic version
+// precedence, returning -1, 0 or 1 if a is less than, equal to or greater
+// than b. Build metadata such as "+ent" is ignored.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+// comparePrerelease compares prerelease identifiers per the semver spec,
+// where a version without a prerelease has higher precedence.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := compareInt(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := compareIdentifier(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// compareIdentifier compares alphanumeric prerelease identifiers. Those
+// with a shared alphabetic prefix and a numeric suffix, such as "rc2" and
+// "rc10", are compared numerically to match HashiCorp's release naming.
+func compareIdentifier(a, b string) int {
+	ap, an := splitNumericSuffix(a)
+	bp, bn := splitNumericSuffix(b)
+	if ap == bp && an != "" && bn != "" {
+		ai, _ := strconv.Atoi(an)
+		bi, _ := strconv.Atoi(bn)
+		return compareInt(ai, bi)
+	}
+	return strings.Compare(a, b)
+}
+
+func splitNumericSuffix(s string) (string, string) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	return s[:i], s[i:]
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package hashicorpreleases
+
+import (
+	"context"
+	"errors"
+)
+
+// SkipProduct may be returned by a WalkReleases callback to skip the
+// remaining releases of the current product
+var SkipProduct = errors.New("skip this product")
+
+// WalkReleases fetches the product list and, one product at a time,
+// pages through all releases of each product, newest first, calling fn
+// for every release. Only one page is held in memory at a time. If fn
+// returns SkipProduct, the walk moves on to the next product; any other
+// error stops the walk and is returned.
+func (c *Client) WalkReleases(ctx context.Context, fn func(product string, r Release) error) error {
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		err := c.pageReleases(ctx, product, &ReleaseOptions{Limit: maxLimit}, func(page ReleasesResponse) error {
+			for _, r := range page {
+				if err := fn(product, r); err != nil {
+					if errors.Is(err, SkipProduct) {
+						return errStopPaging
+					}
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}